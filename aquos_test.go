@@ -0,0 +1,518 @@
+package aquos
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTV is a TV answering the commands of the tests over TCP.
+type fakeTV struct {
+	l net.Listener
+
+	mu     sync.Mutex
+	silent bool   // do not answer
+	model  string // answer to MNRD, rejected if empty
+	power  bool
+	volume int
+	hpos   string
+	conns  []net.Conn
+}
+
+func newFakeTV(t *testing.T) *fakeTV {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tv := &fakeTV{l: l}
+	go tv.serve()
+	return tv
+}
+
+func (tv *fakeTV) addr() string {
+	return tv.l.Addr().String()
+}
+
+func (tv *fakeTV) close() {
+	tv.l.Close()
+	tv.dropAll()
+}
+
+// dropAll closes the connections accepted so far.
+func (tv *fakeTV) dropAll() {
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	for _, conn := range tv.conns {
+		conn.Close()
+	}
+	tv.conns = nil
+}
+
+func (tv *fakeTV) serve() {
+	for {
+		conn, err := tv.l.Accept()
+		if err != nil {
+			return
+		}
+		tv.mu.Lock()
+		tv.conns = append(tv.conns, conn)
+		tv.mu.Unlock()
+		go tv.handle(conn)
+	}
+}
+
+func (tv *fakeTV) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\r')
+		if err != nil {
+			return
+		}
+		res := tv.answer(strings.TrimSuffix(line, "\r"))
+		if res != "" {
+			fmt.Fprintf(conn, "%s\r", res)
+		}
+	}
+}
+
+func (tv *fakeTV) answer(frame string) string {
+	if len(frame) != 8 {
+		return "ERR"
+	}
+	cmd, arg := frame[:4], strings.TrimSpace(frame[4:])
+
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	if tv.silent {
+		return ""
+	}
+
+	switch cmd {
+	case "POWR":
+		switch arg {
+		case "?":
+			if tv.power {
+				return "1"
+			}
+			return "0"
+		case "0", "1":
+			tv.power = arg == "1"
+			return "OK"
+		}
+	case "VOLM":
+		if arg == "?" {
+			return strconv.Itoa(tv.volume)
+		}
+		n, err := strconv.Atoi(arg)
+		if err == nil {
+			tv.volume = n
+			return "OK"
+		}
+	case "RCKY", "RSPW":
+		return "OK"
+	case "MNRD":
+		if tv.model != "" {
+			return tv.model
+		}
+	case "HPOS":
+		if arg != "?" {
+			tv.hpos = arg
+			return "OK"
+		}
+		if tv.hpos == "" {
+			return "0"
+		}
+		return tv.hpos
+	case "AVMD":
+		// the AV mode stays standard, as on inputs without a game mode
+		if arg == "?" {
+			return "1"
+		}
+		return "OK"
+	}
+	return "ERR"
+}
+
+func newTestClient(tv *fakeTV) *Client {
+	return &Client{Address: tv.addr(), CommandInterval: -1}
+}
+
+func TestConcurrentCommands(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				var err error
+				switch (i + j) % 4 {
+				case 0:
+					err = c.SetVolume(ctx, i)
+				case 1:
+					_, err = c.Volume(ctx)
+				case 2:
+					err = c.Power(ctx, true)
+				case 3:
+					_, err = c.PowerState(ctx)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestConcurrentCloseAndCommands(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				// commands may fail while the connection is closed
+				c.PowerState(ctx)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 10; j++ {
+			c.Close()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	wg.Wait()
+
+	// the client connects again after Close
+	_, err := c.PowerState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConnectionDroppedByTV(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := c.Power(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tv.dropAll()
+	// wait for readLoop to notice
+	time.Sleep(50 * time.Millisecond)
+
+	on, err := c.PowerState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !on {
+		t.Error("PowerState = false, want true")
+	}
+}
+
+func TestHeartbeatAndIdleTimeout(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	c.HeartbeatInterval = time.Millisecond
+	c.IdleTimeout = 20 * time.Millisecond
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := c.Volume(ctx)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentEvents(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			c.Events()
+			c.PowerState(ctx)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			c.Events()
+			c.Close()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestCloseDuringCommand(t *testing.T) {
+	testCloseDuringCommand(t, nil)
+}
+
+// TestCloseDuringCommandReconnect checks that a command failed by Close
+// neither reconnects nor keeps Close waiting.
+func TestCloseDuringCommandReconnect(t *testing.T) {
+	testCloseDuringCommand(t, &ReconnectPolicy{MinBackoff: time.Millisecond})
+}
+
+func testCloseDuringCommand(t *testing.T, reconnect *ReconnectPolicy) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	tv.mu.Lock()
+	tv.silent = true
+	tv.mu.Unlock()
+	c := newTestClient(tv)
+	c.Reconnect = reconnect
+	c.Retry = &RetryPolicy{Retryable: func(error) bool { return true }}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := c.PowerState(ctx)
+		errc <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	err := c.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Close took %v", d)
+	}
+	err = <-errc
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("PowerState error = %v, want ErrClosed", err)
+	}
+
+	// closing again is harmless
+	err = c.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		model string
+		ok    bool
+	}{
+		{"", true}, // rejected, but an AQUOS answer
+		{"LC-60LE650U", true},
+		{"HTTP/1.1 400 Bad Request", false},
+	}
+	for _, tt := range tests {
+		tv := newFakeTV(t)
+		tv.mu.Lock()
+		tv.model = tt.model
+		tv.mu.Unlock()
+
+		got, ok := probe(context.Background(), tv.addr(), time.Second)
+		if ok != tt.ok {
+			t.Errorf("probe with model %q = %v, want %v", tt.model, ok, tt.ok)
+		}
+		if ok && got.ModelName != tt.model {
+			t.Errorf("probe with model %q: ModelName = %q", tt.model, got.ModelName)
+		}
+		tv.close()
+	}
+}
+
+func TestProxyHandshakeTimeout(t *testing.T) {
+	// a proxy accepting the connection but never answering
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	c := &Client{
+		Address:         "192.0.2.1:10002",
+		Proxy:           &url.URL{Scheme: "socks5", Host: l.Addr().String()},
+		CommandInterval: -1,
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = c.PowerState(ctx)
+	var nerr net.Error
+	if !errors.As(err, &nerr) || !nerr.Timeout() {
+		t.Errorf("PowerState error = %v, want a net.Error timeout", err)
+	}
+}
+
+func TestNewClientAddress(t *testing.T) {
+	for _, addr := range []string{"", "192.0.2.1", "aquos.local"} {
+		_, err := NewClient(addr)
+		if err == nil {
+			t.Errorf("NewClient(%q) succeeded", addr)
+		}
+	}
+	for _, addr := range []string{"192.0.2.1:10002", "[2001:db8::1]:10002", "aquos.local:10002"} {
+		_, err := NewClient(addr)
+		if err != nil {
+			t.Errorf("NewClient(%q): %v", addr, err)
+		}
+	}
+}
+
+func TestHorizontalPosition(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, pos := range []int{5, -5, -999} {
+		err := c.SetHorizontalPosition(ctx, pos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.HorizontalPosition(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != pos {
+			t.Errorf("HorizontalPosition = %d after SetHorizontalPosition(%d)", got, pos)
+		}
+	}
+}
+
+// TestConnectRejectedIdentity checks that Connect succeeds with a TV
+// rejecting some identity queries, as older firmwares do with SWVN and
+// IPPV.
+func TestConnectRejectedIdentity(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	tv.mu.Lock()
+	tv.model = "LC-60LE650U"
+	tv.mu.Unlock()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := c.Connect(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := c.DeviceInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModelName != "LC-60LE650U" || info.SoftwareVersion != "" || info.IPProtocolVersion != "" {
+		t.Errorf("DeviceInfo = %+v", info)
+	}
+}
+
+func TestEnableGameMode(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	err := c.EnableGameMode(context.Background())
+	if !errors.Is(err, ErrNotApplied) {
+		t.Errorf("EnableGameMode error = %v, want ErrNotApplied", err)
+	}
+}
+
+func TestHoldKeyInterval(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.close()
+	c := newTestClient(tv)
+	defer c.Close()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		err := c.HoldKey(context.Background(), KeyVolumeUp, interval)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("HoldKey with interval %v error = %v, want ErrInvalidArgument", interval, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package aquostest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+)
+
+// A Clock is a fake aquos.Clock whose time only moves on Advance, so
+// that timeouts can be tested without waiting for them. It is safe for
+// concurrent use.
+type Clock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers map[*timer]bool
+}
+
+// NewClock returns a Clock set to now.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{
+		now:    now,
+		timers: make(map[*timer]bool),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer returns a timer firing when the clock is advanced by d.
+func (c *Clock) NewTimer(d time.Duration) aquos.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timer{
+		clock: c,
+		when:  c.now.Add(d),
+		c:     make(chan time.Time, 1),
+	}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	c.timers[t] = true
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward by d, firing the timers due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	for t := range c.timers {
+		if !t.when.After(c.now) {
+			t.c <- c.now
+			delete(c.timers, t)
+		}
+	}
+	c.cond.Broadcast()
+}
+
+// BlockUntil waits until n timers are pending, e.g. until the client
+// waits for a response, before calling Advance.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+type timer struct {
+	clock *Clock
+	when  time.Time
+	c     chan time.Time
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	pending := t.clock.timers[t]
+	delete(t.clock.timers, t)
+	t.clock.cond.Broadcast()
+	return pending
+}
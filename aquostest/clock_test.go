@@ -0,0 +1,95 @@
+package aquostest_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+	"github.com/noocsharp/go-aquos/aquostest"
+)
+
+// silentTV returns a client connected over a pipe to a TV sending prompt
+// and then reading the lines it is sent without answering them.
+func silentTV(clock *aquostest.Clock, prompt string) (*aquos.Client, <-chan string) {
+	lines := make(chan string, 10)
+	c := &aquos.Client{
+		CommandInterval: -1,
+		TimeSource:      clock,
+		Open: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			client, server := net.Pipe()
+			go func() {
+				defer server.Close()
+				if prompt != "" {
+					io.WriteString(server, prompt)
+				}
+				r := bufio.NewReader(server)
+				for {
+					line, err := r.ReadString('\r')
+					if err != nil {
+						return
+					}
+					lines <- line
+				}
+			}()
+			return client, nil
+		},
+	}
+	return c, lines
+}
+
+func TestLoginTimeout(t *testing.T) {
+	clock := aquostest.NewClock(time.Unix(0, 0))
+	c, lines := silentTV(clock, "Login:")
+	c.Username = "admin"
+	c.Password = "secret"
+	c.LoginTimeout = time.Second
+	defer c.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.Connect(context.Background(), "")
+	}()
+
+	// the client sent the username and waits for the password prompt
+	<-lines
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	err := <-errc
+	if !errors.Is(err, aquos.ErrTimeout) {
+		t.Errorf("Connect error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	clock := aquostest.NewClock(time.Unix(0, 0))
+	c, lines := silentTV(clock, "")
+	c.CommandTimeout = 5 * time.Second
+	defer c.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := c.Volume(context.Background())
+		errc <- err
+	}()
+
+	<-lines
+	clock.BlockUntil(1)
+	clock.Advance(4 * time.Second)
+	select {
+	case err := <-errc:
+		t.Fatalf("Volume returned %v before the timeout", err)
+	default:
+	}
+	clock.Advance(time.Second)
+
+	err := <-errc
+	if !errors.Is(err, aquos.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Volume error = %v, want ErrTimeout", err)
+	}
+}
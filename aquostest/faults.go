@@ -0,0 +1,134 @@
+package aquostest
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Faults describes the faults of an unreliable link to inject into the
+// connections of a client, to exercise its timeout and reconnection
+// code. The zero value injects none.
+type Faults struct {
+	// Latency delays each read and each write.
+	Latency time.Duration
+
+	// WriteChunk, if positive, splits the writes into chunks of at most
+	// WriteChunk bytes, each delayed by Latency, as a frame split across
+	// TCP segments.
+	WriteChunk int
+
+	// DropRate is the probability of dropping each byte read.
+	DropRate float64
+
+	// DisconnectAfter, if positive, closes the connection once it has
+	// written that many bytes, in the middle of the write if it ends
+	// there.
+	DisconnectAfter int
+
+	// Seed seeds the choice of the bytes dropped, so that a failing test
+	// can be reproduced.
+	Seed int64
+}
+
+// Wrap returns rwc with the faults injected.
+func (f Faults) Wrap(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	return &faultyConn{
+		ReadWriteCloser: rwc,
+		f:               f,
+		rand:            rand.New(rand.NewSource(f.Seed)),
+	}
+}
+
+// Open returns open injecting the faults into the connections it opens,
+// to be set as aquos.Client.Open.
+func (f Faults) Open(open func(ctx context.Context) (io.ReadWriteCloser, error)) func(ctx context.Context) (io.ReadWriteCloser, error) {
+	return func(ctx context.Context) (io.ReadWriteCloser, error) {
+		rwc, err := open(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return f.Wrap(rwc), nil
+	}
+}
+
+type faultyConn struct {
+	io.ReadWriteCloser
+	f Faults
+
+	rmu  sync.Mutex // guards rand
+	rand *rand.Rand
+
+	wmu     sync.Mutex // serializes writes and guards written
+	written int
+}
+
+func (c *faultyConn) Read(p []byte) (int, error) {
+	for {
+		if c.f.Latency > 0 {
+			time.Sleep(c.f.Latency)
+		}
+		n, err := c.ReadWriteCloser.Read(p)
+		n = c.drop(p[:n])
+		if n > 0 || err != nil {
+			return n, err
+		}
+		// every byte was dropped, a Read returning nothing would be
+		// taken as a stalled reader
+	}
+}
+
+// drop removes the dropped bytes of p and returns the number of bytes
+// left.
+func (c *faultyConn) drop(p []byte) int {
+	if c.f.DropRate <= 0 {
+		return len(p)
+	}
+
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+
+	n := 0
+	for _, b := range p {
+		if c.rand.Float64() >= c.f.DropRate {
+			p[n] = b
+			n++
+		}
+	}
+	return n
+}
+
+func (c *faultyConn) Write(p []byte) (int, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		chunk := p[n:]
+		if c.f.WriteChunk > 0 && len(chunk) > c.f.WriteChunk {
+			chunk = chunk[:c.f.WriteChunk]
+		}
+		disconnect := false
+		if c.f.DisconnectAfter > 0 && c.written+len(chunk) >= c.f.DisconnectAfter {
+			chunk = chunk[:c.f.DisconnectAfter-c.written]
+			disconnect = true
+		}
+
+		if c.f.Latency > 0 {
+			time.Sleep(c.f.Latency)
+		}
+		m, err := c.ReadWriteCloser.Write(chunk)
+		n += m
+		c.written += m
+		if err != nil {
+			return n, err
+		}
+		if disconnect {
+			c.ReadWriteCloser.Close()
+			return n, io.ErrClosedPipe
+		}
+	}
+	return n, nil
+}
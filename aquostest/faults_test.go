@@ -0,0 +1,83 @@
+package aquostest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+	"github.com/noocsharp/go-aquos/aquostest"
+)
+
+func TestFaultsLatency(t *testing.T) {
+	s := aquostest.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	c.Open = aquostest.Faults{Latency: time.Millisecond, WriteChunk: 1}.Open(c.Open)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := c.SetVolume(ctx, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := c.Volume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 25 {
+		t.Errorf("Volume = %d, want 25", v)
+	}
+}
+
+func TestFaultsDisconnect(t *testing.T) {
+	s := aquostest.NewServer()
+	defer s.Close()
+
+	for _, reconnect := range []bool{false, true} {
+		c := s.Client()
+		open, conns := c.Open, 0
+		c.Open = func(ctx context.Context) (io.ReadWriteCloser, error) {
+			conns++
+			if conns > 1 {
+				return open(ctx)
+			}
+			// drop the first connection in the middle of the frame
+			return aquostest.Faults{DisconnectAfter: 5}.Open(open)(ctx)
+		}
+		if reconnect {
+			c.Reconnect = &aquos.ReconnectPolicy{MinBackoff: time.Millisecond}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := c.Volume(ctx)
+		if reconnect && err != nil {
+			t.Errorf("Volume with Reconnect: %v", err)
+		}
+		if !reconnect && err == nil {
+			t.Error("Volume succeeded on a dropped connection")
+		}
+		cancel()
+		c.Close()
+	}
+}
+
+func TestFaultsDroppedResponse(t *testing.T) {
+	s := aquostest.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	c.Open = aquostest.Faults{DropRate: 1}.Open(c.Open)
+	c.CommandTimeout = 50 * time.Millisecond
+	defer c.Close()
+
+	_, err := c.Volume(context.Background())
+	if !errors.Is(err, aquos.ErrTimeout) {
+		t.Errorf("Volume error = %v, want ErrTimeout", err)
+	}
+}
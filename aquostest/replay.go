@@ -0,0 +1,131 @@
+package aquostest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/noocsharp/go-aquos"
+)
+
+// A Replayer plays the part of the TV of a transcript recorded with
+// aquos.Recorder, so that a session captured in the field can be turned
+// into a regression test. It checks that the client sends the same bytes
+// as in the transcript and answers with the recorded ones, without the
+// recorded delays.
+type Replayer struct {
+	entries []aquos.TranscriptEntry
+
+	mu     sync.Mutex
+	next   int // index of the next entry to replay
+	err    error
+	opened bool
+	done   chan struct{}
+}
+
+// NewReplayer returns a Replayer of the transcript read from r.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	entries, err := aquos.ReadTranscript(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{
+		entries: entries,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Client returns a Client connected to the replayer over an in-memory
+// pipe. The transcript is replayed once; the client cannot connect again.
+func (p *Replayer) Client() *aquos.Client {
+	return &aquos.Client{
+		CommandInterval: -1,
+		Open:            p.open,
+	}
+}
+
+func (p *Replayer) open(ctx context.Context) (io.ReadWriteCloser, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.opened {
+		return nil, aquos.ErrClosed
+	}
+	p.opened = true
+
+	client, server := net.Pipe()
+	go p.serve(server)
+	return client, nil
+}
+
+// Err waits for the client to close the connection and returns the first
+// difference from the transcript, if any, or an error if the transcript
+// was not replayed to the end.
+func (p *Replayer) Err() error {
+	p.mu.Lock()
+	opened := p.opened
+	p.mu.Unlock()
+	if opened {
+		<-p.done
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	if p.next < len(p.entries) {
+		return fmt.Errorf("transcript replayed up to entry %d of %d", p.next, len(p.entries))
+	}
+	return nil
+}
+
+func (p *Replayer) serve(conn net.Conn) {
+	defer close(p.done)
+	defer conn.Close()
+
+	for i, e := range p.entries {
+		if e.Sent {
+			buf := make([]byte, len(e.Data))
+			_, err := io.ReadFull(conn, buf)
+			if err != nil {
+				return
+			}
+			if !bytes.Equal(buf, e.Data) {
+				p.fail(fmt.Errorf("transcript entry %d: client sent %q, want %q", i+1, buf, e.Data))
+				return
+			}
+		} else {
+			_, err := conn.Write(e.Data)
+			if err != nil {
+				return
+			}
+		}
+
+		p.mu.Lock()
+		p.next = i + 1
+		p.mu.Unlock()
+	}
+
+	// anything sent after the end of the transcript is unexpected
+	buf := make([]byte, 64)
+	n, _ := conn.Read(buf)
+	if n > 0 {
+		p.fail(fmt.Errorf("client sent %q after the end of the transcript", buf[:n]))
+	}
+	io.Copy(ioutil.Discard, conn)
+}
+
+func (p *Replayer) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err == nil {
+		p.err = err
+	}
+}
@@ -0,0 +1,70 @@
+package aquostest_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+	"github.com/noocsharp/go-aquos/aquostest"
+)
+
+func session(ctx context.Context, c *aquos.Client) (int, error) {
+	err := c.SetVolume(ctx, 25)
+	if err != nil {
+		return 0, err
+	}
+	return c.Volume(ctx)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := aquostest.NewServer()
+	defer s.Close()
+
+	var transcript bytes.Buffer
+	rec := aquos.NewRecorder(&transcript)
+	c := s.Client()
+	c.Recorder = rec
+	_, err := session(ctx, c)
+	c.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := aquostest.NewReplayer(strings.NewReader(transcript.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = p.Client()
+	volume, err := session(ctx, c)
+	c.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume != 25 {
+		t.Errorf("Volume = %d, want 25", volume)
+	}
+	if err := p.Err(); err != nil {
+		t.Error(err)
+	}
+
+	// a different session does not match the transcript
+	p, err = aquostest.NewReplayer(strings.NewReader(transcript.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = p.Client()
+	c.SetVolume(ctx, 26)
+	c.Close()
+	if err := p.Err(); err == nil {
+		t.Error("Err = nil for a different session")
+	}
+}
@@ -0,0 +1,394 @@
+// Package aquostest provides a fake AQUOS TV for testing code using the
+// aquos package without real hardware.
+package aquostest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/noocsharp/go-aquos"
+)
+
+// State is the simulated state of a Server.
+type State struct {
+	Power       bool
+	StandbyMode aquos.StandbyMode
+	Input       aquos.InputSource
+	Volume      int
+	Mute        bool
+	SleepTimer  aquos.SleepTimer
+	Channel     int // analog channel
+}
+
+// DefaultState is the state a Server starts in.
+var DefaultState = State{
+	Power:   true,
+	Input:   aquos.InputHDMI1,
+	Volume:  10,
+	Channel: 1,
+}
+
+// A Server is a fake AQUOS TV speaking the IP control protocol. It
+// answers the commands of the aquos package and tracks the state they
+// change. It is safe for concurrent use.
+type Server struct {
+	// Addr is the address the server listens on, set by Start.
+	Addr string
+
+	// Listener, if set before Start, is used instead of listening on
+	// a free port of the loopback interface.
+	Listener net.Listener
+
+	// Identity answered to TVNM1, MNRD1, SWVN1 and IPPV1. The fields must
+	// not be changed after Start.
+	Name              string
+	ModelName         string
+	SoftwareVersion   string
+	IPProtocolVersion string
+
+	// Password, if set, makes the server require a login. The server
+	// asks for the password only if Username is empty.
+	Username string
+	Password string
+
+	// Handler, if set, is called before the built-in commands and may
+	// answer any command. It is called with the server locked.
+	Handler func(cmd, arg string) (res string, ok bool)
+
+	wg sync.WaitGroup
+
+	mu     sync.Mutex
+	state  State
+	conns  map[net.Conn]bool
+	closed bool
+}
+
+// NewServer starts and returns a new Server listening on the loopback
+// interface. The caller should call Close when finished.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new Server that is not started, so that
+// its identity and login can be configured before calling Start.
+func NewUnstartedServer() *Server {
+	return &Server{
+		Name:              "AQUOS",
+		ModelName:         "LC-60LE650U",
+		SoftwareVersion:   "1.0",
+		IPProtocolVersion: "2",
+		state:             DefaultState,
+		conns:             make(map[net.Conn]bool),
+	}
+}
+
+// Start starts the server on Listener, or on a free port of the loopback
+// interface if it is not set.
+func (s *Server) Start() {
+	if s.Listener == nil {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			panic(fmt.Sprintf("aquostest: failed to listen: %v", err))
+		}
+		s.Listener = l
+	}
+	l := s.Listener
+	s.Addr = l.Addr().String()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.Serve(conn)
+		}
+	}()
+}
+
+// Close stops the server, closes its connections and waits for them to
+// be done.
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	if s.Listener != nil {
+		s.Listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// CloseClientConnections closes the open connections, as a TV dropping
+// its clients would, without stopping the server.
+func (s *Server) CloseClientConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// Serve serves conn in the background until it is closed. A server that
+// is closed closes conn at once.
+func (s *Server) Serve(conn net.Conn) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+		return
+	}
+	s.conns[conn] = true
+	// added under mu so that Close does not wait before it is counted
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			conn.Close()
+		}()
+
+		s.handle(conn)
+	}()
+}
+
+// Client returns a Client connected to the server over an in-memory pipe
+// rather than TCP, without the pacing of commands a real TV needs.
+func (s *Server) Client() *aquos.Client {
+	return &aquos.Client{
+		Username:        s.Username,
+		Password:        s.Password,
+		CommandInterval: -1,
+		Open: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			client, server := net.Pipe()
+			s.Serve(server)
+			return client, nil
+		},
+	}
+}
+
+// State returns the simulated state.
+func (s *Server) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// SetState replaces the simulated state.
+func (s *Server) SetState(state State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = state
+}
+
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+
+	if s.Password != "" && !s.login(conn, r) {
+		return
+	}
+
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return
+		}
+		if line == "" {
+			continue
+		}
+
+		_, err = fmt.Fprintf(conn, "%s\r", s.answer(line))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) login(conn net.Conn, r *bufio.Reader) bool {
+	if s.Username != "" {
+		io.WriteString(conn, "Login:")
+		username, err := readLine(r)
+		if err != nil {
+			return false
+		}
+		io.WriteString(conn, "\r\nPassword:")
+		password, err := readLine(r)
+		if err != nil {
+			return false
+		}
+		if username != s.Username || password != s.Password {
+			io.WriteString(conn, "\r\nLogin incorrect\r\n")
+			return false
+		}
+	} else {
+		io.WriteString(conn, "Password:")
+		password, err := readLine(r)
+		if err != nil || password != s.Password {
+			io.WriteString(conn, "\r\nLogin incorrect\r\n")
+			return false
+		}
+	}
+
+	io.WriteString(conn, "\r\n")
+	return true
+}
+
+// readLine reads a line terminated by CR or LF.
+func readLine(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if c == '\r' || c == '\n' {
+			return b.String(), nil
+		}
+		b.WriteByte(c)
+	}
+}
+
+// answer returns the response to the command line.
+func (s *Server) answer(line string) string {
+	if len(line) < 4 || len(line) > 8 {
+		return "ERR"
+	}
+	cmd, arg := line[:4], strings.TrimSpace(line[4:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Handler != nil {
+		if res, ok := s.Handler(cmd, arg); ok {
+			return res
+		}
+	}
+
+	st := &s.state
+	switch cmd {
+	case "TVNM":
+		return s.Name
+	case "MNRD":
+		return s.ModelName
+	case "SWVN":
+		return s.SoftwareVersion
+	case "IPPV":
+		return s.IPProtocolVersion
+	case "POWR":
+		if arg == "?" {
+			return boolArg(st.Power)
+		}
+		switch arg {
+		case "0":
+			st.Power = false
+			return "OK"
+		case "1":
+			if st.StandbyMode != aquos.StandbyModeIP && !st.Power {
+				// the power on command is ignored over IP
+				return "ERR"
+			}
+			st.Power = true
+			return "OK"
+		}
+		return "ERR"
+	case "RSPW":
+		return setInt(arg, 0, 2, func(n int) { st.StandbyMode = aquos.StandbyMode(n) })
+	}
+
+	if !st.Power {
+		return "ERR"
+	}
+
+	switch cmd {
+	case "VOLM":
+		if arg == "?" {
+			return strconv.Itoa(st.Volume)
+		}
+		return setInt(arg, 0, 100, func(n int) { st.Volume = n })
+	case "MUTE":
+		switch arg {
+		case "?":
+			if st.Mute {
+				return "1"
+			}
+			return "2"
+		case "0":
+			st.Mute = !st.Mute
+		case "1":
+			st.Mute = true
+		case "2":
+			st.Mute = false
+		default:
+			return "ERR"
+		}
+		return "OK"
+	case "IAVD":
+		if arg == "?" {
+			return strconv.Itoa(int(st.Input))
+		}
+		return setInt(arg, 1, int(aquos.MaxInputSource), func(n int) { st.Input = aquos.InputSource(n) })
+	case "ITVD":
+		st.Input = aquos.InputTV
+		return "OK"
+	case "ITGD":
+		st.Input = st.Input%aquos.InputPC + 1
+		return "OK"
+	case "OFTM":
+		if arg == "?" {
+			return strconv.Itoa(int(st.SleepTimer))
+		}
+		return setInt(arg, 0, 5, func(n int) { st.SleepTimer = aquos.SleepTimer(n) })
+	case "DCCH":
+		if arg == "?" {
+			return strconv.Itoa(st.Channel)
+		}
+		return setInt(arg, 1, 135, func(n int) { st.Channel = n })
+	case "CHUP":
+		st.Channel = st.Channel%135 + 1
+		return "OK"
+	case "CHDW":
+		st.Channel = (st.Channel+133)%135 + 1
+		return "OK"
+	case "RCKY":
+		return setInt(arg, 0, 99, func(int) {})
+	}
+
+	return "ERR"
+}
+
+func boolArg(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// setInt calls set with the numeric arg if it is within min - max.
+func setInt(arg string, min, max int, set func(int)) string {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < min || n > max {
+		return "ERR"
+	}
+	set(n)
+	return "OK"
+}
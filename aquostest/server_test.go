@@ -0,0 +1,100 @@
+package aquostest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+	"github.com/noocsharp/go-aquos/aquostest"
+)
+
+func TestServer(t *testing.T) {
+	s := aquostest.NewServer()
+	defer s.Close()
+
+	c := &aquos.Client{CommandInterval: -1, AutoWake: true}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := c.Connect(ctx, s.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.ModelName(); got != s.ModelName {
+		t.Errorf("ModelName = %q, want %q", got, s.ModelName)
+	}
+
+	err = c.SetVolume(ctx, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.ChangeInput(ctx, aquos.InputHDMI3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Power(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := s.State()
+	if st.Volume != 30 || st.Input != aquos.InputHDMI3 || st.Power || st.StandbyMode != aquos.StandbyModeIP {
+		t.Errorf("State = %+v", st)
+	}
+
+	// the model needs IP control in standby, which Power enabled with
+	// AutoWake
+	err = c.Power(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerLogin(t *testing.T) {
+	for _, username := range []string{"admin", ""} {
+		s := aquostest.NewUnstartedServer()
+		s.Username = username
+		s.Password = "secret"
+		s.Start()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		c := s.Client()
+		on, err := c.PowerState(ctx)
+		if err != nil || !on {
+			t.Errorf("username %q: PowerState = %v, %v", username, on, err)
+		}
+		c.Close()
+
+		c = s.Client()
+		c.Password = "wrong"
+		_, err = c.PowerState(ctx)
+		if !errors.Is(err, aquos.ErrLoginFailed) {
+			t.Errorf("username %q: PowerState error = %v, want ErrLoginFailed", username, err)
+		}
+		c.Close()
+
+		cancel()
+		s.Close()
+	}
+}
+
+func TestServerCloseWhileOpening(t *testing.T) {
+	s := aquostest.NewServer()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			c := s.Client()
+			c.PowerState(context.Background())
+			c.Close()
+		}
+	}()
+	s.Close()
+	<-done
+}
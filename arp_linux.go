@@ -0,0 +1,37 @@
+package aquos
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+)
+
+// lookupARP returns the MAC address of ip in the ARP table of the kernel.
+func lookupARP(ip net.IP) (string, error) {
+	f, err := os.Open("/proc/net/arp")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// IP address  HW type  Flags  HW address  Mask  Device
+	s := bufio.NewScanner(f)
+	s.Scan() // header
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 4 || !ip.Equal(net.ParseIP(fields[0])) {
+			continue
+		}
+		if fields[3] == "00:00:00:00:00:00" {
+			// incomplete entry
+			break
+		}
+		return fields[3], nil
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+
+	return "", ErrNoMAC
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package aquos
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+func lookupARP(ip net.IP) (string, error) {
+	return "", fmt.Errorf("ARP lookup is not supported on %s", runtime.GOOS)
+}
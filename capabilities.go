@@ -0,0 +1,64 @@
+package aquos
+
+import (
+	"context"
+	"errors"
+)
+
+// Capabilities reports which queries the TV answers. A command whose query
+// is rejected is usually not supported by the model at all.
+type Capabilities struct {
+	Power              bool // POWR
+	Input              bool // IAVD
+	Volume             bool // VOLM
+	Mute               bool // MUTE
+	SleepTimer         bool // OFTM
+	AVMode             bool // AVMD
+	ViewMode           bool // WIDE
+	Mode3D             bool // TDCH
+	HorizontalPosition bool // HPOS
+	VerticalPosition   bool // VPOS
+	Clock              bool // CLCK
+	Phase              bool // PHSE
+	AnalogChannel      bool // DCCH
+	DigitalAirChannel  bool // DA2P
+}
+
+// Probe issues the query of each command supported by the client and
+// reports which of them the TV answers.
+// Note that the channel queries also depend on the tuned channel.
+func (c *Client) Probe(ctx context.Context) (Capabilities, error) {
+	var caps Capabilities
+	probes := []struct {
+		cmd string
+		ok  *bool
+	}{
+		{"POWR", &caps.Power},
+		{"IAVD", &caps.Input},
+		{"VOLM", &caps.Volume},
+		{"MUTE", &caps.Mute},
+		{"OFTM", &caps.SleepTimer},
+		{"AVMD", &caps.AVMode},
+		{"WIDE", &caps.ViewMode},
+		{"TDCH", &caps.Mode3D},
+		{"HPOS", &caps.HorizontalPosition},
+		{"VPOS", &caps.VerticalPosition},
+		{"CLCK", &caps.Clock},
+		{"PHSE", &caps.Phase},
+		{"DCCH", &caps.AnalogChannel},
+		{"DA2P", &caps.DigitalAirChannel},
+	}
+
+	for _, p := range probes {
+		_, err := c.sendCommand(ctx, p.cmd, "?")
+		switch {
+		case err == nil:
+			*p.ok = true
+		case errors.Is(err, ErrCommandRejected):
+		default:
+			return caps, err
+		}
+	}
+
+	return caps, nil
+}
@@ -0,0 +1,107 @@
+package aquos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// A ChannelKind is the kind of a broadcast channel.
+type ChannelKind int
+
+// Channel kinds.
+const (
+	ChannelAnalog ChannelKind = iota
+	ChannelDigitalAir
+)
+
+// A Channel is a channel of the TV tuner.
+// Minor is only used by digital channels.
+type Channel struct {
+	Kind  ChannelKind
+	Major int
+	Minor int
+}
+
+func (ch Channel) String() string {
+	if ch.Kind == ChannelAnalog {
+		return strconv.Itoa(ch.Major)
+	}
+	return fmt.Sprintf("%d.%d", ch.Major, ch.Minor)
+}
+
+// CurrentChannel returns the tuned channel: an analog channel as reported
+// by the DCCH query, or a digital air channel as reported by the DA2P query.
+func (c *Client) CurrentChannel(ctx context.Context) (Channel, error) {
+	// the analog query fails while a digital channel is tuned
+	n, err := c.queryInt(ctx, "DCCH")
+	if err == nil {
+		return Channel{Kind: ChannelAnalog, Major: n}, nil
+	}
+	if !errors.Is(err, ErrCommandRejected) {
+		return Channel{}, err
+	}
+
+	res, err := c.sendCommand(ctx, "DA2P", "?")
+	if err != nil {
+		return Channel{}, err
+	}
+	ch, ok := parseDigitalAirChannel(res)
+	if !ok {
+		return Channel{}, &CommandError{Cmd: "DA2P", Arg: "?", Raw: res, Err: ErrInvalidResponse}
+	}
+
+	return ch, nil
+}
+
+// parseDigitalAirChannel parses the response to DA2P?, the major and
+// the minor numbers on two digits each.
+func parseDigitalAirChannel(s string) (Channel, bool) {
+	if len(s) != 4 {
+		return Channel{}, false
+	}
+	major, ok := parseNumber(s[:2])
+	if !ok {
+		return Channel{}, false
+	}
+	minor, ok := parseNumber(s[2:])
+	if !ok {
+		return Channel{}, false
+	}
+
+	return Channel{Kind: ChannelDigitalAir, Major: major, Minor: minor}, true
+}
+
+// ScanChannels steps through the channels with ChannelUp and returns the
+// channels tuned on the way without duplicates. It stops when the channel
+// tuned at the start comes around again or after max steps.
+func (c *Client) ScanChannels(ctx context.Context, max int) ([]Channel, error) {
+	first, err := c.CurrentChannel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := []Channel{first}
+	seen := map[Channel]bool{first: true}
+	for i := 0; i < max; i++ {
+		err = c.ChannelUp(ctx)
+		if err != nil {
+			return channels, err
+		}
+
+		ch, err := c.CurrentChannel(ctx)
+		if err != nil {
+			return channels, err
+		}
+		if ch == first {
+			break
+		}
+		if !seen[ch] {
+			seen[ch] = true
+			channels = append(channels, ch)
+		}
+	}
+
+	return channels, nil
+}
@@ -0,0 +1,61 @@
+package aquos
+
+import (
+	"context"
+	"time"
+)
+
+// A Clock tells the time and makes timers. The client uses the system
+// clock unless Client.TimeSource is set, e.g. to a fake clock in tests; see
+// aquostest.Clock.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// A Timer is a timer made by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel receiving the time when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, as time.Timer.Stop.
+	Stop() bool
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time { return t.t.C }
+func (t systemTimer) Stop() bool          { return t.t.Stop() }
+
+func (c *Client) clock() Clock {
+	if c.TimeSource == nil {
+		return systemClock{}
+	}
+	return c.TimeSource
+}
+
+func (c *Client) now() time.Time {
+	return c.clock().Now()
+}
+
+// sleep waits for d or until ctx is done.
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	t := c.clock().NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C():
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+// Command aquos-sim emulates an AQUOS TV on the network, for developing
+// and demonstrating automations without a TV.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/noocsharp/go-aquos/aquostest"
+)
+
+func run() (int, error) {
+	var addr string
+	var verbose bool
+	s := aquostest.NewUnstartedServer()
+	flag.StringVar(&addr, "addr", ":10002", "TCP address to listen on")
+	flag.StringVar(&s.Name, "name", s.Name, "TV name")
+	flag.StringVar(&s.ModelName, "model", s.ModelName, "Model name")
+	flag.StringVar(&s.SoftwareVersion, "version", s.SoftwareVersion, "Software version")
+	flag.StringVar(&s.Username, "user", "", "Username required to login")
+	flag.StringVar(&s.Password, "pass", "", "Password required to login (no login if empty)")
+	flag.BoolVar(&verbose, "v", false, "Log the commands received")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage : %s [options]
+
+options:
+`, os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if verbose {
+		s.Handler = func(cmd, arg string) (string, bool) {
+			log.Printf("%s %s", cmd, arg)
+			return "", false
+		}
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return 1, err
+	}
+	s.Listener = l
+	s.Start()
+	defer s.Close()
+	log.Printf("%s (%s) listening on %s", s.Name, s.ModelName, s.Addr)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	<-sig
+
+	return 0, nil
+}
+
+func main() {
+	code, err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error : %v\n", err)
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+)
+
+func runDiscover() (int, error) {
+	var d aquos.Discoverer
+	var subnets string
+	var save bool
+	var registry string
+	fs := flag.NewFlagSet("discover", flag.ExitOnError)
+	fs.DurationVar(&d.Timeout, "timeout", 3*time.Second, "Discovery duration")
+	fs.BoolVar(&d.Scan, "scan", false, "Scan the local subnets for the control port")
+	fs.StringVar(&subnets, "subnet", "", "Comma-separated subnets to scan (implies -scan)")
+	fs.BoolVar(&d.Identify, "identify", true, "Connect to each TV to query its identity")
+	fs.StringVar(&d.Username, "user", "", "Username")
+	fs.StringVar(&d.Password, "pass", "", "Password")
+	fs.BoolVar(&save, "save", false, "Add the TVs found to the registry")
+	fs.StringVar(&registry, "registry", "", "Registry file (default in the user config directory)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage : %s discover [options]
+
+options:
+`, os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[2:])
+
+	if subnets != "" {
+		d.Scan = true
+		d.Subnets = strings.Split(subnets, ",")
+	}
+
+	tvs, err := d.Discover(context.Background())
+	if err != nil {
+		return 1, err
+	}
+	if len(tvs) == 0 {
+		fmt.Fprintln(os.Stderr, "no TV found.")
+		return 1, nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tNAME\tMODEL\tVIA")
+	for _, tv := range tvs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tv.Addr, tv.Name, tv.ModelName, tv.Via)
+	}
+	w.Flush()
+
+	if !save {
+		return 0, nil
+	}
+
+	r, err := openRegistry(registry)
+	if err != nil {
+		return 1, err
+	}
+	for _, tv := range tvs {
+		name := registryName(r, tv)
+		dev := aquos.Device{
+			Address:   tv.Addr,
+			Username:  d.Username,
+			Password:  d.Password,
+			ModelName: tv.ModelName,
+		}
+		if tv.Info != nil {
+			dev.MAC = tv.Info.MAC
+		}
+		r.Set(name, dev)
+		fmt.Printf("saved %s as %q\n", tv.Addr, name)
+	}
+	err = r.Save()
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// openRegistry loads the registry at path, or at the default path if
+// path is empty.
+func openRegistry(path string) (*aquos.Registry, error) {
+	if path == "" {
+		var err error
+		path, err = aquos.DefaultRegistryPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r := aquos.NewRegistry(aquos.FileStore{Path: path})
+	err := r.Load()
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// registryName returns the name to save tv under: the existing entry of
+// its address, or else its name or host made unique.
+func registryName(r *aquos.Registry, tv aquos.DiscoveredTV) string {
+	for _, name := range r.Names() {
+		if d, _ := r.Get(name); d.Address == tv.Addr {
+			return name
+		}
+	}
+
+	base := tv.Name
+	if base == "" {
+		base, _, _ = net.SplitHostPort(tv.Addr)
+	}
+	name := base
+	for i := 2; ; i++ {
+		if _, ok := r.Get(name); !ok {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+}
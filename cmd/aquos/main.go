@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log"
 	"net"
 	"os"
 	"strconv"
@@ -15,14 +16,18 @@ func run() (int, error) {
 	var port int
 	var username string
 	var password string
+	var verbose bool
 	flag.IntVar(&port, "port", 10002, "TCP port")
 	flag.StringVar(&username, "user", "", "Username")
 	flag.StringVar(&password, "pass", "", "Password")
+	flag.BoolVar(&verbose, "v", false, "Log diagnostic messages")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Usage : %s [options] host
+        %s discover [options]
+        %s wake [options] name
 
 options:
-`, os.Args[0])
+`, os.Args[0], os.Args[0], os.Args[0])
 		flag.PrintDefaults()
 	}
 	flag.Parse()
@@ -40,8 +45,13 @@ options:
 		Username: username,
 		Password: password,
 	}
+	if verbose {
+		client.Logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+
+	ctx := context.Background()
 
-	err := client.Connect(context.Background(), net.JoinHostPort(host, strconv.Itoa(port)))
+	err := client.Connect(ctx, net.JoinHostPort(host, strconv.Itoa(port)))
 	if err != nil {
 		return 1, err
 	}
@@ -62,25 +72,25 @@ loop:
 		case 0:
 			break loop
 		case 1:
-			err = client.Power(true)
+			err = client.Power(ctx, true)
 		case 2:
-			err = client.Power(false)
+			err = client.Power(ctx, false)
 		case 3:
-			err = client.ToggleInput()
+			err = client.ToggleInput(ctx)
 		case 4:
-			err = client.ChangeInputTV()
+			err = client.ChangeInputTV(ctx)
 		case 5:
 			source := selectInputSource()
-			err = client.ChangeInput(source)
+			err = client.ChangeInput(ctx, aquos.InputSource(source))
 		case 6:
-			err = client.ChannelUp()
+			err = client.ChannelUp(ctx)
 		case 7:
-			err = client.ChannelDown()
+			err = client.ChannelDown(ctx)
 		case 8:
-			volume := selectVolume()
-			err = client.SetVolume(volume)
+			volume := selectVolume(client.Model().MaxVolume)
+			err = client.SetVolume(ctx, volume)
 		case 9:
-			volume, err := client.Volume()
+			volume, err := client.Volume(ctx)
 			if err == nil {
 				fmt.Printf("Volume : %d\n", volume)
 			}
@@ -152,18 +162,18 @@ func selectInputSource() (source int) {
 	return
 }
 
-func selectVolume() (volume int) {
+func selectVolume(max int) (volume int) {
 	for {
-		fmt.Print(`
-Volume 0 - 100
-> `)
+		fmt.Printf(`
+Volume 0 - %d
+> `, max)
 		_, err := fmt.Scan(&volume)
 		if err != nil {
 			fmt.Println(err)
 			continue
 		}
 
-		if volume < 0 {
+		if volume < 0 || volume > max {
 			fmt.Println("invalid volume")
 			continue
 		}
@@ -175,6 +185,16 @@ Volume 0 - 100
 }
 
 func main() {
+	run := run
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "discover":
+			run = runDiscover
+		case "wake":
+			run = runWake
+		}
+	}
+
 	code, err := run()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error : %v\n", err)
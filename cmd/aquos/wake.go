@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+)
+
+func runWake() (int, error) {
+	var mac string
+	var wait time.Duration
+	var registry string
+	fs := flag.NewFlagSet("wake", flag.ExitOnError)
+	fs.StringVar(&mac, "mac", "", "MAC address, instead of the one in the registry")
+	fs.DurationVar(&wait, "wait", 0, "Wait up to this long for the control port to answer")
+	fs.StringVar(&registry, "registry", "", "Registry file (default in the user config directory)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage : %s wake [options] name
+        %s wake -mac address [options] [host]
+
+options:
+`, os.Args[0], os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[2:])
+
+	var addr string
+	if mac == "" {
+		if fs.NArg() < 1 {
+			fmt.Fprintln(os.Stderr, "name is not specified.")
+			fs.Usage()
+			return 1, nil
+		}
+		r, err := openRegistry(registry)
+		if err != nil {
+			return 1, err
+		}
+		d, ok := r.Get(fs.Arg(0))
+		if !ok {
+			return 1, fmt.Errorf("TV %q is not in the registry", fs.Arg(0))
+		}
+		if d.MAC == "" {
+			return 1, fmt.Errorf("MAC address of %q is unknown", fs.Arg(0))
+		}
+		mac, addr = d.MAC, d.Address
+	} else if fs.NArg() > 0 {
+		addr = net.JoinHostPort(fs.Arg(0), aquos.DefaultPort)
+	}
+
+	err := aquos.WakeOnLAN(mac)
+	if err != nil {
+		return 1, err
+	}
+	if wait <= 0 {
+		return 0, nil
+	}
+	if addr == "" {
+		return 1, fmt.Errorf("host is not specified, cannot wait")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+	err = waitPort(ctx, addr, mac)
+	if err != nil {
+		return 1, err
+	}
+	fmt.Printf("%s is up\n", addr)
+
+	return 0, nil
+}
+
+// waitPort dials addr until it accepts a connection or ctx is done,
+// sending Wake-on-LAN to mac again between the attempts in case the
+// packet was lost.
+func waitPort(ctx context.Context, addr, mac string) error {
+	var d net.Dialer
+	for {
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s does not answer: %w", addr, err)
+		case <-time.After(time.Second):
+		}
+		aquos.WakeOnLAN(mac)
+	}
+}
@@ -0,0 +1,205 @@
+package aquos
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPort is the control port of AQUOS.
+const DefaultPort = "10002"
+
+// DefaultDiscoverTimeout is the duration of the discovery when
+// Discoverer.Timeout is not set.
+var DefaultDiscoverTimeout = 3 * time.Second
+
+// DefaultIdentifyTimeout bounds the identification of a TV when
+// Discoverer.IdentifyTimeout is not set.
+var DefaultIdentifyTimeout = 5 * time.Second
+
+// A DiscoveredTV is a TV found on the local network.
+type DiscoveredTV struct {
+	Addr      string // address of the control port, host:port
+	Name      string // advertised name, if any
+	ModelName string // advertised model name, if any
+	Via       string // discovery method that found the TV first
+
+	// Info is the identity reported by the TV when Discoverer.Identify
+	// is set, or nil if it could not be queried.
+	Info *DeviceInfo
+
+	// Err is the error of the identification, if any.
+	Err error
+}
+
+// A Discoverer finds AQUOS TVs on the local network. The zero value is
+// ready to use.
+type Discoverer struct {
+	// Timeout is the duration of the discovery. Zero means
+	// DefaultDiscoverTimeout.
+	Timeout time.Duration
+	// Services are the mDNS service types browsed. Nil means MDNSServices;
+	// an empty slice disables mDNS.
+	Services []string
+
+	// Scan enables probing the control port of every address of Subnets,
+	// for networks where multicast is blocked or TVs that advertise
+	// nothing.
+	Scan bool
+
+	// Subnets are the IPv4 subnets scanned, in CIDR notation. Nil means
+	// the subnets of the local interfaces, narrowed to /24.
+	Subnets []string
+
+	// ScanTimeout bounds the probe of a single address. Zero means
+	// DefaultScanTimeout.
+	ScanTimeout time.Duration
+
+	// ScanConcurrency is the number of addresses probed at a time. Zero
+	// means DefaultScanConcurrency.
+	ScanConcurrency int
+
+	// Identify makes Discover connect to each TV found and set its Info,
+	// logging in with Username and Password if set.
+	Identify bool
+	Username string
+	Password string
+
+	// IdentifyTimeout bounds the identification of a TV. Zero means
+	// DefaultIdentifyTimeout.
+	IdentifyTimeout time.Duration
+}
+
+// Discover finds AQUOS TVs on the local network with the default
+// Discoverer.
+func Discover(ctx context.Context) ([]DiscoveredTV, error) {
+	var d Discoverer
+	return d.Discover(ctx)
+}
+
+// Discover runs the discovery methods in parallel for Timeout or until
+// ctx is done, identifies the TVs found if Identify is set, and returns
+// them merged by address and sorted. An error is returned only if every
+// discovery method failed.
+func (d *Discoverer) Discover(ctx context.Context) ([]DiscoveredTV, error) {
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDiscoverTimeout
+	}
+	dctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var tvs []DiscoveredTV
+	index := make(map[string]int)
+	found := func(tv DiscoveredTV) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		i, ok := index[tv.Addr]
+		if !ok {
+			index[tv.Addr] = len(tvs)
+			tvs = append(tvs, tv)
+			return
+		}
+		if tvs[i].Name == "" {
+			tvs[i].Name = tv.Name
+		}
+		if tvs[i].ModelName == "" {
+			tvs[i].ModelName = tv.ModelName
+		}
+	}
+
+	methods := d.methods()
+	if len(methods) == 0 {
+		return nil, errors.New("no discovery method enabled")
+	}
+	errs := make(chan error, len(methods))
+	for _, m := range methods {
+		go func(m discoverFunc) {
+			errs <- m(dctx, found)
+		}(m)
+	}
+	var err error
+	failed := 0
+	for range methods {
+		if e := <-errs; e != nil {
+			err = e
+			failed++
+		}
+	}
+	if failed == len(methods) {
+		return nil, err
+	}
+
+	sort.Slice(tvs, func(i, j int) bool {
+		return tvs[i].Addr < tvs[j].Addr
+	})
+
+	if d.Identify {
+		var wg sync.WaitGroup
+		for i := range tvs {
+			wg.Add(1)
+			go func(tv *DiscoveredTV) {
+				defer wg.Done()
+				tv.Info, tv.Err = d.identify(ctx, tv.Addr)
+				if tv.Info != nil {
+					tv.Name = tv.Info.Name
+					tv.ModelName = tv.Info.ModelName
+				}
+			}(&tvs[i])
+		}
+		wg.Wait()
+	}
+
+	return tvs, nil
+}
+
+// identify connects to the TV at addr and returns its DeviceInfo.
+func (d *Discoverer) identify(ctx context.Context, addr string) (*DeviceInfo, error) {
+	timeout := d.IdentifyTimeout
+	if timeout <= 0 {
+		timeout = DefaultIdentifyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c := &Client{
+		Username: d.Username,
+		Password: d.Password,
+	}
+	defer c.Close()
+
+	err := c.Connect(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// A discoverFunc calls found for each TV it finds until ctx is done.
+type discoverFunc func(ctx context.Context, found func(DiscoveredTV)) error
+
+func (d *Discoverer) methods() []discoverFunc {
+	services := d.Services
+	if services == nil {
+		services = MDNSServices
+	}
+
+	var methods []discoverFunc
+	if len(services) > 0 {
+		methods = append(methods, func(ctx context.Context, found func(DiscoveredTV)) error {
+			return browseMDNS(ctx, services, found)
+		})
+	}
+	if d.Scan {
+		methods = append(methods, d.scan)
+	}
+	return methods
+}
@@ -0,0 +1,86 @@
+package aquos
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrCommandRejected is returned when AQUOS answers ERR to a command.
+	ErrCommandRejected = errors.New("command rejected by AQUOS")
+
+	// ErrInvalidResponse is returned when AQUOS answers a query with
+	// a value the client does not understand.
+	ErrInvalidResponse = errors.New("invalid response from AQUOS")
+
+	// ErrInvalidArgument is returned when an argument is out of the range
+	// accepted by AQUOS. Nothing is sent to AQUOS in that case.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrNotApplied is returned when AQUOS accepted a command but its
+	// state did not change accordingly, see Client.Verify.
+	ErrNotApplied = errors.New("command not applied by AQUOS")
+
+	// ErrNoMAC is returned by Client.Wake when the MAC address of the TV
+	// is not known.
+	ErrNoMAC = errors.New("MAC address of AQUOS unknown")
+
+	// ErrNotConnected is returned when the client has no address or
+	// stream to connect to.
+	ErrNotConnected = errors.New("not connected to AQUOS")
+
+	// ErrLoginFailed is returned when the login is rejected or AQUOS
+	// requires a login the client is not configured for.
+	ErrLoginFailed = errors.New("failed to login")
+
+	// ErrTimeout is returned when AQUOS does not answer in time.
+	ErrTimeout = errors.New("AQUOS does not respond")
+
+	// ErrClosed is returned when the connection is closed by Close while
+	// a command is in progress, or cannot be opened again.
+	ErrClosed = errors.New("connection already closed")
+)
+
+// timeoutError is returned when the context of a command expires while
+// waiting for AQUOS. It matches both ErrTimeout and the context error.
+type timeoutError struct {
+	err error
+}
+
+func (e timeoutError) Error() string {
+	return ErrTimeout.Error() + ": " + e.err.Error()
+}
+
+func (e timeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+func (e timeoutError) Unwrap() error {
+	return e.err
+}
+
+// Timeout reports true, as net.Error does.
+func (e timeoutError) Timeout() bool {
+	return true
+}
+
+// A CommandError records a command AQUOS rejected or answered with an
+// unexpected response.
+type CommandError struct {
+	Cmd string // command, e.g. "VOLM"
+	Arg string // parameter, e.g. "?"
+	Raw string // response of AQUOS
+	Err error  // ErrCommandRejected, ErrInvalidResponse or a parse error
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s%s: %v (response %q)", e.Cmd, e.Arg, e.Err, e.Raw)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+func notApplied(what string, got, want interface{}) error {
+	return fmt.Errorf("%w: %s is %v, want %v", ErrNotApplied, what, got, want)
+}
@@ -0,0 +1,47 @@
+package aquos
+
+import "sync/atomic"
+
+// EventBufferSize is the number of unsolicited lines buffered by the
+// channel returned by Events. Further lines are dropped until the channel
+// is read.
+const EventBufferSize = 16
+
+// Events returns a channel receiving the lines AQUOS sends while no
+// command is waiting for a reply, such as power state changes pushed by
+// some models or banners sent after login. Lines are dropped when the
+// channel is full, so reading it is optional. The channel is never closed.
+func (c *Client) Events() <-chan string {
+	c.eventsOnce.Do(func() {
+		c.events = make(chan string, EventBufferSize)
+	})
+	return c.events
+}
+
+// expect marks whether a command or the login is waiting for lines from
+// AQUOS. Lines arriving otherwise are routed to Events.
+func (c *Client) expect(pending bool) {
+	var v int32
+	if pending {
+		v = 1
+	}
+	atomic.StoreInt32(&c.pending, v)
+}
+
+// isReply reports whether readLoop should hand line to the command
+// waiting for a reply rather than to Events. A login prompt is always
+// a reply: it means AQUOS requires a login the client did not make.
+func (c *Client) isReply(line string) bool {
+	return atomic.LoadInt32(&c.pending) != 0 || isPrompt(line)
+}
+
+// event delivers an unsolicited line to Events, dropping it if the
+// channel is full.
+func (c *Client) event(line string) {
+	c.Events() // make the channel
+	select {
+	case c.events <- line:
+	default:
+		c.logf("aquos: event dropped: %q", line)
+	}
+}
@@ -0,0 +1,45 @@
+package aquos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Encode returns the frame of the command cmd with the parameter arg as
+// sent to AQUOS: cmd, arg padded with spaces to 4 characters, and
+// a carriage return. cmd must be 4 printable ASCII characters and arg
+// printable ASCII characters; only TVNM takes more than 4 (see SetName).
+func Encode(cmd, arg string) ([]byte, error) {
+	if len(cmd) != 4 || !isPrintable(cmd) {
+		return nil, fmt.Errorf("%w: command (%q)", ErrInvalidArgument, cmd)
+	}
+	if len(arg) > MaxNameLength || !isPrintable(arg) {
+		return nil, fmt.Errorf("%w: parameter (%q)", ErrInvalidArgument, arg)
+	}
+
+	return []byte(fmt.Sprintf("%s%-4s\r", cmd, arg)), nil
+}
+
+// Decode returns the value of a response line of AQUOS without its
+// padding and terminator, such as "OK" or "30". It returns an error
+// wrapping ErrCommandRejected for ERR and ErrInvalidResponse for a blank
+// line.
+func Decode(line string) (string, error) {
+	value := strings.TrimSpace(line)
+	switch value {
+	case "":
+		return "", ErrInvalidResponse
+	case "ERR":
+		return "", ErrCommandRejected
+	}
+	return value, nil
+}
+
+func isPrintable(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package aquos
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		cmd, arg string
+		want     string
+		err      error
+	}{
+		{"POWR", "1", "POWR1   \r", nil},
+		{"POWR", "?", "POWR?   \r", nil},
+		{"POWR", "0", "POWR0   \r", nil},
+		{"VOLM", "30", "VOLM30  \r", nil},
+		{"VOLM", "0030", "VOLM0030\r", nil},
+		{"IAVD", "1", "IAVD1   \r", nil},
+		{"ITVD", "-", "ITVD-   \r", nil},
+		{"DA2P", "0501", "DA2P0501\r", nil},
+		{"RCKY", "46", "RCKY46  \r", nil},
+		{"MUTE", "", "MUTE    \r", nil},
+		{"TVNM", "1", "TVNM1   \r", nil},
+		{"TVNM", "Living Room", "TVNMLiving Room\r", nil},
+		{"POW", "1", "", ErrInvalidArgument},
+		{"POWER", "1", "", ErrInvalidArgument},
+		{"", "", "", ErrInvalidArgument},
+		{"POW\r", "1", "", ErrInvalidArgument},
+		{"POWR", "1\r", "", ErrInvalidArgument},
+		{"POWR", "\x00", "", ErrInvalidArgument},
+		{"POWR", "é", "", ErrInvalidArgument},
+		{"TVNM", "seventeen chars!!", "", ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		got, err := Encode(tt.cmd, tt.arg)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Encode(%q, %q) error = %v, want %v", tt.cmd, tt.arg, err, tt.err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("Encode(%q, %q) = %q, want %q", tt.cmd, tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+		err  error
+	}{
+		{"OK", "OK", nil},
+		{"1", "1", nil},
+		{"0", "0", nil},
+		{"30", "30", nil},
+		{"  30", "30", nil},
+		{"30  ", "30", nil},
+		{"OK\r", "OK", nil},
+		{"OK\r\n", "OK", nil},
+		{"LC-60LE650U", "LC-60LE650U", nil},
+		{"Living Room", "Living Room", nil},
+		{"ERR", "", ErrCommandRejected},
+		{"ERR\r", "", ErrCommandRejected},
+		{" ERR ", "", ErrCommandRejected},
+		{"ERROR", "ERROR", nil},
+		{"", "", ErrInvalidResponse},
+		{"   ", "", ErrInvalidResponse},
+		{"\r\n", "", ErrInvalidResponse},
+	}
+	for _, tt := range tests {
+		got, err := Decode(tt.line)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Decode(%q) error = %v, want %v", tt.line, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Decode(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// TestEncodeDecode checks that a frame read back as a line decodes to the
+// command and its padding, as with firmwares echoing the commands.
+func TestEncodeDecode(t *testing.T) {
+	for _, cmd := range []string{"POWR", "VOLM", "IAVD", "MUTE", "RCKY"} {
+		for _, arg := range []string{"", "?", "1", "12", "123", "1234"} {
+			frame, err := Encode(cmd, arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(frame) != 9 {
+				t.Errorf("Encode(%q, %q) = %q, want 9 bytes", cmd, arg, frame)
+			}
+			got, err := Decode(string(frame))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != cmd+arg {
+				t.Errorf("Decode(%q) = %q, want %q", frame, got, cmd+arg)
+			}
+		}
+	}
+}
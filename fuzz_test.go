@@ -0,0 +1,119 @@
+//go:build go1.18
+// +build go1.18
+
+package aquos
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func FuzzScanLines(f *testing.F) {
+	for _, s := range []string{
+		"OK\r",
+		"ERR\r\n",
+		"12\r\r34",
+		"Login:",
+		"\r\nPassword:",
+		"a:b:c",
+		"LC-60\x00LE650U\r",
+		"\r\n:\r\n",
+		"",
+	} {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := bufio.NewScanner(bytes.NewReader(data))
+		s.Split(scanLines)
+
+		var got []byte
+		for s.Scan() {
+			token := s.Bytes()
+			if len(token) == 0 {
+				t.Fatalf("empty token from %q", data)
+			}
+			for _, b := range token {
+				if isIgnore(b) {
+					t.Fatalf("token %q from %q contains a separator", token, data)
+				}
+			}
+			got = append(got, token...)
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("scanning %q: %v", data, err)
+		}
+
+		// every byte but the separators ends up in a token
+		var want []byte
+		for _, b := range data {
+			if !isIgnore(b) {
+				want = append(want, b)
+			}
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("tokens of %q are %q, want %q", data, got, want)
+		}
+	})
+}
+
+func FuzzParseNumber(f *testing.F) {
+	for _, s := range []string{"0", "30", " 5", "0030", "-1", "+1", "1e3", "99999999999", "", "\x00", "-5", "-999", "--1", "- 1", "-"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if n, ok := parseSignedNumber(s); ok {
+			want, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || n != want {
+				t.Fatalf("parseSignedNumber(%q) = %d, strconv.Atoi = %d, %v", s, n, want, err)
+			}
+		}
+
+		n, ok := parseNumber(s)
+		if !ok {
+			return
+		}
+		if n < 0 {
+			t.Fatalf("parseNumber(%q) = %d", s, n)
+		}
+		if m, ok := parseSignedNumber(s); !ok || m != n {
+			t.Fatalf("parseSignedNumber(%q) = %d, %v, parseNumber = %d", s, m, ok, n)
+		}
+	})
+}
+
+func FuzzParseDigitalAirChannel(f *testing.F) {
+	for _, s := range []string{"0501", "1234", "12", "-1-2", "ab12", "12345", ""} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		ch, ok := parseDigitalAirChannel(s)
+		if !ok {
+			return
+		}
+		if ch.Kind != ChannelDigitalAir || ch.Major < 0 || ch.Major > 99 || ch.Minor < 0 || ch.Minor > 99 {
+			t.Fatalf("parseDigitalAirChannel(%q) = %+v", s, ch)
+		}
+	})
+}
+
+func FuzzParseFlag(f *testing.F) {
+	for _, s := range []string{"0", "1", "2", " 1", "10", "", "OK"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		on, ok := parseFlag(s, "1", "0")
+		if !ok {
+			return
+		}
+		if want := strings.TrimSpace(s) == "1"; on != want {
+			t.Fatalf("parseFlag(%q) = %v", s, on)
+		}
+	})
+}
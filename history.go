@@ -0,0 +1,60 @@
+package aquos
+
+import "time"
+
+// A HistoryEntry records a command sent by a Client.
+type HistoryEntry struct {
+	Time     time.Time     // when the command was sent
+	Duration time.Duration // time until the reply or the error
+	Frame    string        // command as sent, without the terminator
+	Response string        // reply of AQUOS, empty if none
+	Err      error         // error of the command, if any
+}
+
+// History returns the last Client.HistorySize commands sent to AQUOS,
+// oldest first, including heartbeat queries and failed commands. The
+// commands sent during login are not recorded.
+func (c *Client) History() []HistoryEntry {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	return c.ordered()
+}
+
+// ordered returns the history oldest first. It must be called with
+// historyMu held.
+func (c *Client) ordered() []HistoryEntry {
+	h := make([]HistoryEntry, 0, len(c.history))
+	h = append(h, c.history[c.historyAt:]...)
+	return append(h, c.history[:c.historyAt]...)
+}
+
+func (c *Client) record(start time.Time, frame, res string, err error) {
+	e := HistoryEntry{
+		Time:     start,
+		Duration: c.now().Sub(start),
+		Frame:    frame,
+		Response: res,
+		Err:      err,
+	}
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	if len(c.history) > c.HistorySize || (c.historyAt != 0 && len(c.history) < c.HistorySize) {
+		// HistorySize changed, keep the newest entries in order
+		h := c.ordered()
+		if len(h) > c.HistorySize {
+			h = h[len(h)-c.HistorySize:]
+		}
+		c.history = h
+		c.historyAt = 0
+	}
+
+	if len(c.history) < c.HistorySize {
+		c.history = append(c.history, e)
+		return
+	}
+	c.history[c.historyAt] = e
+	c.historyAt = (c.historyAt + 1) % len(c.history)
+}
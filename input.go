@@ -0,0 +1,66 @@
+package aquos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// An InputSource is an input of AQUOS as numbered by the IAVD command.
+type InputSource int
+
+// InputTV is the TV tuner. It is reported by CurrentInput while the tuner
+// is selected.
+const InputTV InputSource = 0
+
+// Input sources.
+const (
+	InputHDMI1 InputSource = iota + 1
+	InputHDMI2
+	InputHDMI3
+	InputHDMI4
+	InputComponent
+	InputVideo1 // composite
+	InputVideo2 // composite
+	InputPC
+)
+
+// MaxInputSource is the highest input number accepted by the IAVD
+// command. The inputs of a model are listed by Model.Inputs.
+const MaxInputSource InputSource = 9
+
+var inputNames = map[InputSource]string{
+	InputTV:        "TV",
+	InputHDMI1:     "HDMI1",
+	InputHDMI2:     "HDMI2",
+	InputHDMI3:     "HDMI3",
+	InputHDMI4:     "HDMI4",
+	InputComponent: "Component",
+	InputVideo1:    "Video1",
+	InputVideo2:    "Video2",
+	InputPC:        "PC",
+}
+
+func (s InputSource) String() string {
+	if name, ok := inputNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Input%d", int(s))
+}
+
+// ParseInputSource parses the name of an input source as returned by
+// String, ignoring case and spaces (e.g. "hdmi 1"), or an input number.
+func ParseInputSource(s string) (InputSource, error) {
+	name := strings.Replace(s, " ", "", -1)
+	for source, n := range inputNames {
+		if strings.EqualFold(name, n) {
+			return source, nil
+		}
+	}
+
+	if n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(name), "input")); err == nil && n >= 0 {
+		return InputSource(n), nil
+	}
+
+	return 0, fmt.Errorf("%w: input source (%s)", ErrInvalidArgument, s)
+}
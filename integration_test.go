@@ -0,0 +1,134 @@
+package aquos_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+)
+
+// The integration tests run against a real TV when AQUOS_TEST_ADDR is set
+// to the address of its control port (e.g. 192.168.1.10:10002), with
+// AQUOS_TEST_USER and AQUOS_TEST_PASS if it requires a login. They only
+// query the TV, unless AQUOS_TEST_VOLUME is set, which allows changing
+// the volume by one step and back:
+//
+//	AQUOS_TEST_ADDR=192.168.1.10:10002 go test -run Integration -v
+func integrationClient(t *testing.T) (*aquos.Client, context.Context, func()) {
+	t.Helper()
+
+	addr := os.Getenv("AQUOS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("AQUOS_TEST_ADDR is not set")
+	}
+
+	c := &aquos.Client{
+		Username: os.Getenv("AQUOS_TEST_USER"),
+		Password: os.Getenv("AQUOS_TEST_PASS"),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	done := func() {
+		c.Close()
+		cancel()
+	}
+
+	err := c.Connect(ctx, addr)
+	if err != nil {
+		done()
+		t.Fatal(err)
+	}
+	return c, ctx, done
+}
+
+func TestIntegrationIdentity(t *testing.T) {
+	c, ctx, done := integrationClient(t)
+	defer done()
+
+	info, err := c.DeviceInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModelName == "" {
+		t.Error("empty model name")
+	}
+	t.Logf("%+v", info)
+
+	m := c.Model()
+	if m.Prefix == "" {
+		t.Logf("model %s is not in Models, using DefaultModel", info.ModelName)
+	}
+}
+
+func TestIntegrationQueries(t *testing.T) {
+	c, ctx, done := integrationClient(t)
+	defer done()
+
+	on, err := c.PowerState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !on {
+		t.Skip("the TV is in standby")
+	}
+
+	volume, err := c.Volume(ctx)
+	if err != nil {
+		t.Error(err)
+	} else if max := c.Model().MaxVolume; volume < 0 || volume > max {
+		t.Errorf("Volume = %d, out of 0 - %d", volume, max)
+	}
+
+	input, err := c.CurrentInput(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+	muted, err := c.MuteState(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("volume %d, input %s, muted %v", volume, input, muted)
+
+	caps, err := c.Probe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%+v", caps)
+}
+
+func TestIntegrationVolumeRoundTrip(t *testing.T) {
+	if os.Getenv("AQUOS_TEST_VOLUME") == "" {
+		t.Skip("AQUOS_TEST_VOLUME is not set")
+	}
+	c, ctx, done := integrationClient(t)
+	defer done()
+
+	volume, err := c.Volume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := volume + 1
+	if want > c.Model().MaxVolume {
+		want = volume - 1
+	}
+
+	err = c.SetVolume(ctx, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := c.SetVolume(ctx, volume)
+		if err != nil {
+			t.Errorf("restoring volume %d: %v", volume, err)
+		}
+	}()
+
+	got, err := c.Volume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Volume = %d after SetVolume(%d)", got, want)
+	}
+}
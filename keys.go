@@ -0,0 +1,187 @@
+package aquos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// A RemoteKey is a key of the remote control, sent with the RCKY command.
+type RemoteKey int
+
+// Remote control keys.
+const (
+	Key0 RemoteKey = iota
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+	KeyDot
+	KeyEnt
+	KeyPower
+	KeyDisplay
+	KeyPowerSource
+	KeyRewind
+	KeyPlay
+	KeyFastForward
+	KeyPause
+	KeySkipBack
+	KeyStop
+	KeySkipForward
+	KeyRec
+	KeyOption
+	KeySleep
+	KeyRecStop
+	KeyPowerSaving
+	KeyCC
+	KeyAVMode
+	KeyViewMode
+	KeyFlashback
+	KeyMute
+	KeyVolumeDown
+	KeyVolumeUp
+	KeyChannelUp
+	KeyChannelDown
+	KeyInput
+	KeyBrowser
+	KeyMenu
+	KeySmartCentral
+	KeyEnter
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyReturn
+	KeyExit
+)
+
+// Remote control keys following the gap in the numbering.
+const (
+	KeyA RemoteKey = iota + 50
+	KeyB
+	KeyC
+	KeyD
+	KeyFreeze
+	KeyFavoriteApp1
+	KeyFavoriteApp2
+	KeyFavoriteApp3
+	Key3D
+	KeyNetflix
+	KeyAAL
+	KeyManual
+)
+
+// SendKey sends a key press of the remote control.
+func (c *Client) SendKey(ctx context.Context, key RemoteKey) error {
+	if key < 0 || key > 99 {
+		return fmt.Errorf("%w: key (%d)", ErrInvalidArgument, key)
+	}
+
+	arg := strconv.Itoa(int(key))
+	_, err := c.sendCommand(ctx, "RCKY", arg)
+	return err
+}
+
+// An App is a smart TV application reachable with a dedicated key.
+// The protocol has no keys for other applications (e.g. YouTube); assign
+// them to one of the favorite application keys on the TV.
+type App int
+
+// Apps.
+const (
+	AppSmartCentral = App(KeySmartCentral)
+	AppNetflix      = App(KeyNetflix)
+	AppFavorite1    = App(KeyFavoriteApp1)
+	AppFavorite2    = App(KeyFavoriteApp2)
+	AppFavorite3    = App(KeyFavoriteApp3)
+)
+
+// LaunchApp launches app.
+func (c *Client) LaunchApp(ctx context.Context, app App) error {
+	switch app {
+	case AppSmartCentral, AppNetflix, AppFavorite1, AppFavorite2, AppFavorite3:
+	default:
+		return fmt.Errorf("%w: app (%d)", ErrInvalidArgument, app)
+	}
+
+	return c.SendKey(ctx, RemoteKey(app))
+}
+
+func (c *Client) keyDelay() time.Duration {
+	if c.KeyDelay <= 0 {
+		return DefaultKeyDelay
+	}
+	return c.KeyDelay
+}
+
+// EnterNumber types the digits of n followed by ENT, e.g. to enter a
+// channel number or a PIN code. Keys are sent KeyDelay apart.
+func (c *Client) EnterNumber(ctx context.Context, n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: number (%d)", ErrInvalidArgument, n)
+	}
+
+	for _, d := range strconv.Itoa(n) {
+		err := c.SendKey(ctx, Key0+RemoteKey(d-'0'))
+		if err != nil {
+			return err
+		}
+		err = c.sleep(ctx, c.keyDelay())
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.SendKey(ctx, KeyEnt)
+}
+
+// Navigate sends keys in order, KeyDelay apart, and stops at the first
+// error. It is meant for walking through menus, e.g.
+//
+//	c.Navigate(ctx, KeyMenu, KeyDown, KeyDown, KeyEnter)
+func (c *Client) Navigate(ctx context.Context, keys ...RemoteKey) error {
+	for i, key := range keys {
+		if i > 0 {
+			err := c.sleep(ctx, c.keyDelay())
+			if err != nil {
+				return err
+			}
+		}
+
+		err := c.SendKey(ctx, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HoldKey emulates holding key down by sending it every interval until ctx
+// is done. It returns nil when ctx is done and the error otherwise. The
+// interval must be positive.
+func (c *Client) HoldKey(ctx context.Context, key RemoteKey, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: interval (%v)", ErrInvalidArgument, interval)
+	}
+
+	for {
+		err := c.SendKey(ctx, key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if c.sleep(ctx, interval) != nil {
+			return nil
+		}
+	}
+}
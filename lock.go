@@ -0,0 +1,105 @@
+package aquos
+
+import (
+	"context"
+	"sync"
+)
+
+// A Priority orders the commands waiting to be sent by a Client.
+// Waiting commands of a higher priority are sent first; commands of the
+// same priority are sent in order.
+type Priority int
+
+// Priorities.
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+
+	numPriorities = iota
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx carrying p as the priority of the
+// commands sent with it. Without it commands have PriorityNormal, except
+// power and mute commands, which have PriorityHigh so that they overtake
+// long-running macros and fades.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func priorityFrom(ctx context.Context) (Priority, bool) {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok || p < PriorityLow || p > PriorityHigh {
+		return PriorityNormal, false
+	}
+	return p, true
+}
+
+// urgent raises the default priority of ctx to PriorityHigh.
+func urgent(ctx context.Context) context.Context {
+	if _, ok := priorityFrom(ctx); ok {
+		return ctx
+	}
+	return WithPriority(ctx, PriorityHigh)
+}
+
+// A cmdLock serializes the commands of a Client. Unlike sync.Mutex,
+// waiting for it can be cancelled with a context, and it is handed over
+// to the waiter with the highest priority of its context. The zero value
+// is an unlocked lock.
+type cmdLock struct {
+	mu      sync.Mutex
+	locked  bool
+	waiters [numPriorities][]chan struct{}
+}
+
+func (l *cmdLock) lock(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.locked {
+		l.locked = true
+		l.mu.Unlock()
+		return nil
+	}
+
+	p, _ := priorityFrom(ctx)
+	ch := make(chan struct{})
+	l.waiters[p] = append(l.waiters[p], ch)
+	l.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	for i, w := range l.waiters[p] {
+		if w == ch {
+			l.waiters[p] = append(l.waiters[p][:i], l.waiters[p][i+1:]...)
+			l.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+	l.mu.Unlock()
+
+	// the lock was handed over while ctx was done
+	l.unlock()
+	return ctx.Err()
+}
+
+func (l *cmdLock) unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for p := numPriorities - 1; p >= 0; p-- {
+		if len(l.waiters[p]) > 0 {
+			ch := l.waiters[p][0]
+			l.waiters[p] = l.waiters[p][1:]
+			close(ch)
+			return
+		}
+	}
+	l.locked = false
+}
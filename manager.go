@@ -0,0 +1,129 @@
+package aquos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// A Manager owns the Clients of a set of TVs, each known by a name,
+// and handles their connections. It is safe for concurrent use.
+type Manager struct {
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewManager returns an empty Manager.
+func NewManager() *Manager {
+	return &Manager{
+		clients: make(map[string]*Client),
+	}
+}
+
+// Add adds the client c under name.
+func (m *Manager) Add(name string, c *Client) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.clients[name]; ok {
+		return fmt.Errorf("client %q already exists", name)
+	}
+	m.clients[name] = c
+
+	return nil
+}
+
+// Remove closes and removes the client named name.
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	c, ok := m.clients[name]
+	delete(m.clients, name)
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("client %q does not exist", name)
+	}
+	return c.Close()
+}
+
+// Client returns the client named name.
+func (m *Manager) Client(name string) (*Client, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.clients[name]
+	return c, ok
+}
+
+// Names returns the sorted names of the clients.
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ConnectAll connects all the clients concurrently and returns the errors
+// of the clients that failed to connect by name, or nil.
+func (m *Manager) ConnectAll(ctx context.Context) map[string]error {
+	return m.each(func(c *Client) error {
+		return c.Connect(ctx, "")
+	})
+}
+
+// Reconnect closes the connection of the client named name and connects
+// it again.
+func (m *Manager) Reconnect(ctx context.Context, name string) error {
+	c, ok := m.Client(name)
+	if !ok {
+		return fmt.Errorf("client %q does not exist", name)
+	}
+	return c.Connect(ctx, "")
+}
+
+// Close closes all the clients and returns the errors by name, or nil.
+// The clients stay in the manager and can be connected again.
+func (m *Manager) Close() map[string]error {
+	return m.each(func(c *Client) error {
+		return c.Close()
+	})
+}
+
+func (m *Manager) each(f func(*Client) error) map[string]error {
+	m.mu.Lock()
+	clients := make(map[string]*Client, len(m.clients))
+	for name, c := range m.clients {
+		clients[name] = c
+	}
+	m.mu.Unlock()
+
+	var mu sync.Mutex
+	var errs map[string]error
+	var wg sync.WaitGroup
+	for name, c := range clients {
+		wg.Add(1)
+		go func(name string, c *Client) {
+			defer wg.Done()
+
+			err := f(c)
+			if err != nil {
+				mu.Lock()
+				if errs == nil {
+					errs = make(map[string]error)
+				}
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, c)
+	}
+	wg.Wait()
+
+	return errs
+}
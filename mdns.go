@@ -0,0 +1,336 @@
+package aquos
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// MDNSServices lists the mDNS service types browsed by Discover. Newer
+// (Android TV) AQUOS models advertise these; older models advertise
+// nothing and are found by scanning only.
+var MDNSServices = []string{
+	"_androidtvremote2._tcp.local.",
+	"_googlecast._tcp.local.",
+	"_airplay._tcp.local.",
+}
+
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// DNS record types.
+const (
+	dnsTypeA   = 1
+	dnsTypePTR = 12
+	dnsTypeTXT = 16
+	dnsTypeSRV = 33
+)
+
+var errMalformedDNS = errors.New("malformed DNS message")
+
+// A dnsRecord is a resource record of a DNS message. Only the fields of
+// its type are set.
+type dnsRecord struct {
+	Name string
+	Type uint16
+	Ptr  string   // PTR
+	Txt  []string // TXT
+	Port uint16   // SRV
+	Host string   // SRV target
+	IP   net.IP   // A
+}
+
+// browseMDNS sends a one-shot mDNS query for services and calls found for
+// each AQUOS answering until ctx is done.
+func browseMDNS(ctx context.Context, services []string, found func(DiscoveredTV)) error {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// answers are sent to the port of the query (legacy unicast)
+	_, err = conn.WriteTo(mdnsQuery(services), mdnsAddr)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetReadDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+
+	m := newMDNSCollector(services)
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		records, err := parseDNS(buf[:n])
+		if err != nil {
+			// not our business
+			continue
+		}
+		for _, tv := range m.add(records) {
+			found(tv)
+		}
+	}
+}
+
+// mdnsCollector gathers the records of the answers until the PTR, SRV, TXT
+// and A records of an instance are known.
+type mdnsCollector struct {
+	services map[string]bool
+	ptrs     map[string]string // instance -> service
+	srvs     map[string]dnsRecord
+	txts     map[string][]string
+	ips      map[string]net.IP // host -> address
+	reported map[string]bool
+}
+
+func newMDNSCollector(services []string) *mdnsCollector {
+	m := &mdnsCollector{
+		services: make(map[string]bool),
+		ptrs:     make(map[string]string),
+		srvs:     make(map[string]dnsRecord),
+		txts:     make(map[string][]string),
+		ips:      make(map[string]net.IP),
+		reported: make(map[string]bool),
+	}
+	for _, s := range services {
+		m.services[strings.ToLower(s)] = true
+	}
+	return m
+}
+
+// add adds records and returns the TVs completed by them.
+func (m *mdnsCollector) add(records []dnsRecord) []DiscoveredTV {
+	for _, r := range records {
+		name := strings.ToLower(r.Name)
+		switch r.Type {
+		case dnsTypePTR:
+			if m.services[name] {
+				m.ptrs[strings.ToLower(r.Ptr)] = name
+			}
+		case dnsTypeSRV:
+			m.srvs[name] = r
+		case dnsTypeTXT:
+			m.txts[name] = r.Txt
+		case dnsTypeA:
+			m.ips[name] = r.IP
+		}
+	}
+
+	var tvs []DiscoveredTV
+	for instance, service := range m.ptrs {
+		if m.reported[instance] {
+			continue
+		}
+		srv, ok := m.srvs[instance]
+		if !ok {
+			continue
+		}
+		ip, ok := m.ips[strings.ToLower(srv.Host)]
+		if !ok {
+			continue
+		}
+
+		tv := DiscoveredTV{
+			Addr: net.JoinHostPort(ip.String(), DefaultPort),
+			Via:  "mdns",
+		}
+		tv.Name = instanceName(srv.Name, service)
+		for _, kv := range m.txts[instance] {
+			k, v := splitTXT(kv)
+			switch k {
+			case "fn", "n":
+				tv.Name = v
+			case "md", "model":
+				tv.ModelName = v
+			}
+		}
+		if !isAQUOS(tv.Name, tv.ModelName) {
+			continue
+		}
+
+		m.reported[instance] = true
+		tvs = append(tvs, tv)
+	}
+	return tvs
+}
+
+// instanceName returns the instance label of the service instance name.
+func instanceName(name, service string) string {
+	if len(name) > len(service) && strings.EqualFold(name[len(name)-len(service):], service) {
+		return strings.TrimSuffix(name[:len(name)-len(service)], ".")
+	}
+	return name
+}
+
+func splitTXT(s string) (key, value string) {
+	i := strings.IndexByte(s, '=')
+	if i < 0 {
+		return strings.ToLower(s), ""
+	}
+	return strings.ToLower(s[:i]), s[i+1:]
+}
+
+// isAQUOS reports whether the advertised name or model looks like a Sharp
+// AQUOS TV, as other devices advertise the same services.
+func isAQUOS(name, model string) bool {
+	for _, s := range []string{name, model} {
+		l := strings.ToLower(s)
+		if strings.Contains(l, "aquos") || strings.Contains(l, "sharp") {
+			return true
+		}
+		for _, m := range Models {
+			if m.Prefix != "" && strings.HasPrefix(s, m.Prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mdnsQuery returns a query for the PTR records of services.
+func mdnsQuery(services []string) []byte {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[4:], uint16(len(services)))
+	for _, s := range services {
+		msg = appendName(msg, s)
+		msg = append(msg, 0, dnsTypePTR, 0, 1) // class IN
+	}
+	return msg
+}
+
+func appendName(b []byte, name string) []byte {
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+// parseDNS returns the resource records of the DNS message msg.
+func parseDNS(msg []byte) ([]dnsRecord, error) {
+	if len(msg) < 12 {
+		return nil, errMalformedDNS
+	}
+	qd := int(binary.BigEndian.Uint16(msg[4:]))
+	rr := int(binary.BigEndian.Uint16(msg[6:])) +
+		int(binary.BigEndian.Uint16(msg[8:])) +
+		int(binary.BigEndian.Uint16(msg[10:]))
+
+	off := 12
+	for i := 0; i < qd; i++ {
+		_, n, err := readName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		off = n + 4 // type and class
+	}
+
+	var records []dnsRecord
+	for i := 0; i < rr; i++ {
+		name, n, err := readName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		off = n
+		if off+10 > len(msg) {
+			return nil, errMalformedDNS
+		}
+		typ := binary.BigEndian.Uint16(msg[off:])
+		length := int(binary.BigEndian.Uint16(msg[off+8:]))
+		off += 10
+		if off+length > len(msg) {
+			return nil, errMalformedDNS
+		}
+		data := msg[off : off+length]
+
+		r := dnsRecord{Name: name, Type: typ}
+		switch typ {
+		case dnsTypeA:
+			if length != 4 {
+				return nil, errMalformedDNS
+			}
+			r.IP = net.IPv4(data[0], data[1], data[2], data[3])
+		case dnsTypePTR:
+			r.Ptr, _, err = readName(msg, off)
+		case dnsTypeSRV:
+			if length < 7 {
+				return nil, errMalformedDNS
+			}
+			r.Port = binary.BigEndian.Uint16(data[4:])
+			r.Host, _, err = readName(msg, off+6)
+		case dnsTypeTXT:
+			for len(data) > 0 {
+				l := int(data[0])
+				if 1+l > len(data) {
+					return nil, errMalformedDNS
+				}
+				r.Txt = append(r.Txt, string(data[1:1+l]))
+				data = data[1+l:]
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, r)
+		off += length
+	}
+
+	return records, nil
+}
+
+// readName reads the possibly compressed domain name at off in msg and
+// returns it with the offset following it.
+func readName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	for jumps := 0; ; {
+		if off >= len(msg) {
+			return "", 0, errMalformedDNS
+		}
+		l := int(msg[off])
+		switch {
+		case l == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.Join(labels, ".") + ".", end, nil
+		case l&0xc0 == 0xc0:
+			if off+1 >= len(msg) {
+				return "", 0, errMalformedDNS
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			jumps++
+			if jumps > 16 {
+				// pointer loop
+				return "", 0, errMalformedDNS
+			}
+			off = (l&0x3f)<<8 | int(msg[off+1])
+		default:
+			if off+1+l > len(msg) {
+				return "", 0, errMalformedDNS
+			}
+			labels = append(labels, string(msg[off+1:off+1+l]))
+			off += 1 + l
+		}
+	}
+}
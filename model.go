@@ -0,0 +1,99 @@
+package aquos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Model describes the capabilities and protocol quirks of a family of
+// AQUOS models.
+type Model struct {
+	// Prefix is the prefix of the model names (as reported by MNRD) of the
+	// family.
+	Prefix string
+
+	// MaxVolume is the maximum volume.
+	MaxVolume int
+
+	// Inputs lists the input sources other than InputTV. Nil means
+	// unknown, in which case any input is accepted.
+	Inputs []InputSource
+
+	// NeedsStandbyIP is set if the power on command is only accepted over
+	// the network after the standby mode has been set to StandbyModeIP.
+	NeedsStandbyIP bool
+}
+
+// DefaultModel is used for models not listed in Models.
+var DefaultModel = Model{
+	MaxVolume: 100,
+}
+
+// Models lists the known model families. The first entry whose Prefix
+// matches the model name is used.
+var Models = []Model{
+	{
+		// LC-xxLE/LC-xxUE/LC-xxUD generations
+		Prefix:    "LC-",
+		MaxVolume: 60,
+		Inputs: []InputSource{
+			InputHDMI1, InputHDMI2, InputHDMI3, InputHDMI4,
+			InputComponent, InputVideo1, InputVideo2, InputPC,
+		},
+		NeedsStandbyIP: true,
+	},
+	{
+		// Android TV generations
+		Prefix:    "4T-C",
+		MaxVolume: 100,
+		Inputs: []InputSource{
+			InputHDMI1, InputHDMI2, InputHDMI3, InputHDMI4,
+			InputVideo1,
+		},
+	},
+	{
+		Prefix:    "2T-C",
+		MaxVolume: 100,
+		Inputs: []InputSource{
+			InputHDMI1, InputHDMI2, InputHDMI3,
+			InputVideo1,
+		},
+	},
+}
+
+// LookupModel returns the model family of the model name, or DefaultModel
+// if it is unknown.
+func LookupModel(name string) Model {
+	for _, m := range Models {
+		if strings.HasPrefix(name, m.Prefix) {
+			return m
+		}
+	}
+	return DefaultModel
+}
+
+// Model returns the model family of the TV identified on Connect.
+func (c *Client) Model() Model {
+	return LookupModel(c.ModelName())
+}
+
+func (c *Client) maxVolume() int {
+	return c.Model().MaxVolume
+}
+
+func (c *Client) checkInput(source InputSource) error {
+	if source < InputHDMI1 || source > MaxInputSource {
+		return fmt.Errorf("%w: input (%d)", ErrInvalidArgument, source)
+	}
+
+	inputs := c.Model().Inputs
+	if inputs == nil {
+		return nil
+	}
+	for _, s := range inputs {
+		if s == source {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: input (%s), %s has no such input", ErrInvalidArgument, source, c.ModelName())
+}
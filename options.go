@@ -0,0 +1,290 @@
+package aquos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// An Option configures a Client created by NewClient.
+type Option func(*Client) error
+
+// NewClient returns a Client for AQUOS at addr configured with opts.
+// Unlike setting the fields of Client, the configuration is validated up
+// front. The client connects on Connect or on the first command.
+func NewClient(addr string, opts ...Option) (*Client, error) {
+	if addr == "" {
+		return nil, errors.New("address is not specified")
+	}
+	_, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		Address: addr,
+	}
+	for _, opt := range opts {
+		err = opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// WithCredentials sets the username and the password to log in with.
+func WithCredentials(username, password string) Option {
+	return func(c *Client) error {
+		if password == "" {
+			return errors.New("password is not specified")
+		}
+		c.Username = username
+		c.Password = password
+		return nil
+	}
+}
+
+// WithCredentialsFunc sets Client.Credentials.
+func WithCredentialsFunc(f func(ctx context.Context) (username, password string, err error)) Option {
+	return func(c *Client) error {
+		if f == nil {
+			return errors.New("credentials func is nil")
+		}
+		c.Credentials = f
+		return nil
+	}
+}
+
+// WithLoginTimeout sets Client.LoginTimeout.
+func WithLoginTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid login timeout (%v)", d)
+		}
+		c.LoginTimeout = d
+		return nil
+	}
+}
+
+// WithPromptTimeout sets Client.PromptTimeout.
+func WithPromptTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid prompt timeout (%v)", d)
+		}
+		c.PromptTimeout = d
+		return nil
+	}
+}
+
+// WithAddresses sets failover addresses (see Client.Addresses), tried
+// after the address passed to NewClient.
+func WithAddresses(addrs ...string) Option {
+	return func(c *Client) error {
+		for _, addr := range addrs {
+			_, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				return err
+			}
+		}
+		c.Addresses = append([]string{c.Address}, addrs...)
+		return nil
+	}
+}
+
+// WithDialer dials AQUOS with d.
+func WithDialer(d *net.Dialer) Option {
+	return func(c *Client) error {
+		if d == nil {
+			return errors.New("dialer is nil")
+		}
+		c.DialContext = d.DialContext
+		return nil
+	}
+}
+
+// WithDialTimeout sets Client.DialTimeout.
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid dial timeout (%v)", d)
+		}
+		c.DialTimeout = d
+		return nil
+	}
+}
+
+// WithKeepAlive sets Client.KeepAlive.
+func WithKeepAlive(d time.Duration) Option {
+	return func(c *Client) error {
+		c.KeepAlive = d
+		return nil
+	}
+}
+
+// WithLocalAddr sets Client.LocalAddr.
+func WithLocalAddr(addr net.Addr) Option {
+	return func(c *Client) error {
+		c.LocalAddr = addr
+		return nil
+	}
+}
+
+// WithProxy sets Client.Proxy from the URL rawurl.
+func WithProxy(rawurl string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return err
+		}
+		switch u.Scheme {
+		case "socks5", "socks5h", "http":
+		default:
+			return fmt.Errorf("unsupported proxy scheme (%s)", u.Scheme)
+		}
+		c.Proxy = u
+		return nil
+	}
+}
+
+// WithCommandInterval sets Client.CommandInterval.
+func WithCommandInterval(d time.Duration) Option {
+	return func(c *Client) error {
+		c.CommandInterval = d
+		return nil
+	}
+}
+
+// WithReconnect sets Client.Reconnect.
+func WithReconnect(p ReconnectPolicy) Option {
+	return func(c *Client) error {
+		if p.MaxAttempts < 0 || p.MinBackoff < 0 || p.MaxBackoff < 0 {
+			return errors.New("invalid reconnect policy")
+		}
+		c.Reconnect = &p
+		return nil
+	}
+}
+
+// WithRetry sets Client.Retry.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) error {
+		if p.MaxAttempts < 0 || p.Backoff < 0 || p.MaxBackoff < 0 || p.Jitter < 0 || p.Jitter > 1 {
+			return errors.New("invalid retry policy")
+		}
+		c.Retry = &p
+		return nil
+	}
+}
+
+// WithHeartbeat sets Client.HeartbeatInterval and Client.OnHeartbeatError.
+func WithHeartbeat(interval time.Duration, onError func(error)) Option {
+	return func(c *Client) error {
+		if interval <= 0 {
+			return fmt.Errorf("invalid heartbeat interval (%v)", interval)
+		}
+		c.HeartbeatInterval = interval
+		c.OnHeartbeatError = onError
+		return nil
+	}
+}
+
+// WithIdleTimeout sets Client.IdleTimeout.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid idle timeout (%v)", d)
+		}
+		c.IdleTimeout = d
+		return nil
+	}
+}
+
+// WithKeyDelay sets Client.KeyDelay.
+func WithKeyDelay(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid key delay (%v)", d)
+		}
+		c.KeyDelay = d
+		return nil
+	}
+}
+
+// WithAutoWake sets Client.AutoWake.
+func WithAutoWake() Option {
+	return func(c *Client) error {
+		c.AutoWake = true
+		return nil
+	}
+}
+
+// WithMAC sets Client.MAC.
+func WithMAC(mac string) Option {
+	return func(c *Client) error {
+		if _, err := net.ParseMAC(mac); err != nil {
+			return fmt.Errorf("invalid MAC address (%s)", mac)
+		}
+		c.MAC = mac
+		return nil
+	}
+}
+
+// WithVerify sets Client.Verify.
+func WithVerify() Option {
+	return func(c *Client) error {
+		c.Verify = true
+		return nil
+	}
+}
+
+// WithHistory sets Client.HistorySize.
+func WithHistory(size int) Option {
+	return func(c *Client) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid history size (%d)", size)
+		}
+		c.HistorySize = size
+		return nil
+	}
+}
+
+// WithRecorder sets Client.Recorder.
+func WithRecorder(r *Recorder) Option {
+	return func(c *Client) error {
+		c.Recorder = r
+		return nil
+	}
+}
+
+// WithLogger sets Client.Logger.
+func WithLogger(l Logger) Option {
+	return func(c *Client) error {
+		c.Logger = l
+		return nil
+	}
+}
+
+// WithCommandTimeout sets Client.CommandTimeout.
+func WithCommandTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid command timeout (%v)", d)
+		}
+		c.CommandTimeout = d
+		return nil
+	}
+}
+
+// WithTimeSource sets Client.TimeSource.
+func WithTimeSource(clock Clock) Option {
+	return func(c *Client) error {
+		c.TimeSource = clock
+		return nil
+	}
+}
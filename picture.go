@@ -0,0 +1,253 @@
+package aquos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// An AVMode is a picture and sound preset of AQUOS.
+type AVMode int
+
+// AV modes.
+const (
+	AVModeStandard     AVMode = 1
+	AVModeMovie        AVMode = 2
+	AVModeGame         AVMode = 3
+	AVModeUser         AVMode = 4
+	AVModeDynamicFixed AVMode = 5
+	AVModeDynamic      AVMode = 6
+	AVModePC           AVMode = 7
+	AVModeXvColor      AVMode = 8
+	AVModeStandard3D   AVMode = 14
+	AVModeMovie3D      AVMode = 15
+	AVModeGame3D       AVMode = 16
+	AVModeAuto         AVMode = 100
+)
+
+func (m AVMode) valid() bool {
+	return (m >= AVModeStandard && m <= AVModeXvColor) ||
+		(m >= AVModeStandard3D && m <= AVModeGame3D) ||
+		m == AVModeAuto
+}
+
+// ToggleAVMode switches to the next AV mode.
+func (c *Client) ToggleAVMode(ctx context.Context) error {
+	_, err := c.sendCommand(ctx, "AVMD", "0")
+	return err
+}
+
+// SetAVMode changes the AV mode.
+func (c *Client) SetAVMode(ctx context.Context, mode AVMode) error {
+	if !mode.valid() {
+		return fmt.Errorf("%w: AV mode (%d)", ErrInvalidArgument, mode)
+	}
+
+	arg := strconv.Itoa(int(mode))
+	_, err := c.sendCommand(ctx, "AVMD", arg)
+	return err
+}
+
+// AVMode returns the current AV mode.
+func (c *Client) AVMode(ctx context.Context) (AVMode, error) {
+	mode, err := c.queryInt(ctx, "AVMD")
+	if err != nil {
+		return 0, err
+	}
+
+	return AVMode(mode), nil
+}
+
+// GameMode reports whether a game AV mode is selected.
+func (c *Client) GameMode(ctx context.Context) (bool, error) {
+	mode, err := c.AVMode(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return mode == AVModeGame || mode == AVModeGame3D, nil
+}
+
+// EnableGameMode selects AVModeGame to reduce the input lag, and confirms
+// that the TV switched to it, returning an error wrapping ErrNotApplied
+// if it did not.
+func (c *Client) EnableGameMode(ctx context.Context) error {
+	err := c.SetAVMode(ctx, AVModeGame)
+	if err != nil {
+		return err
+	}
+
+	on, err := c.GameMode(ctx)
+	if err != nil {
+		return err
+	}
+	if !on {
+		return notApplied("game mode", on, true)
+	}
+
+	return nil
+}
+
+// DisableGameMode selects AVModeStandard.
+func (c *Client) DisableGameMode(ctx context.Context) error {
+	return c.SetAVMode(ctx, AVModeStandard)
+}
+
+// A ViewMode is a view mode (aspect ratio) of AQUOS.
+// Which modes are available depends on whether an AV or a PC input is
+// selected.
+type ViewMode int
+
+// View modes for AV inputs.
+const (
+	ViewModeSideBar    ViewMode = 1
+	ViewModeSStretch   ViewMode = 2
+	ViewModeZoom       ViewMode = 3
+	ViewModeStretch    ViewMode = 4
+	ViewModeFullScreen ViewMode = 9
+	ViewModeAuto       ViewMode = 10
+	ViewModeOriginal   ViewMode = 11
+)
+
+// View modes for PC inputs.
+const (
+	ViewModeNormalPC  ViewMode = 5
+	ViewModeZoomPC    ViewMode = 6
+	ViewModeStretchPC ViewMode = 7
+)
+
+// ViewModeDotByDot is available for both AV and PC inputs.
+const ViewModeDotByDot ViewMode = 8
+
+// ToggleViewMode switches to the next view mode.
+func (c *Client) ToggleViewMode(ctx context.Context) error {
+	_, err := c.sendCommand(ctx, "WIDE", "0")
+	return err
+}
+
+// SetViewMode changes the view mode.
+func (c *Client) SetViewMode(ctx context.Context, mode ViewMode) error {
+	if mode < ViewModeSideBar || mode > ViewModeOriginal {
+		return fmt.Errorf("%w: view mode (%d)", ErrInvalidArgument, mode)
+	}
+
+	arg := strconv.Itoa(int(mode))
+	_, err := c.sendCommand(ctx, "WIDE", arg)
+	return err
+}
+
+// ViewMode returns the current view mode.
+func (c *Client) ViewMode(ctx context.Context) (ViewMode, error) {
+	mode, err := c.queryInt(ctx, "WIDE")
+	if err != nil {
+		return 0, err
+	}
+
+	return ViewMode(mode), nil
+}
+
+// The ranges of the position, clock and phase adjustments depend on the
+// model and the selected input. AQUOS answers an error for a value out of
+// range.
+
+func (c *Client) setAdjustment(ctx context.Context, cmd string, v int) error {
+	// the value must fit in the 4 characters parameter
+	if v < -999 || v > 9999 {
+		return fmt.Errorf("%w: value (%d)", ErrInvalidArgument, v)
+	}
+
+	arg := strconv.Itoa(v)
+	_, err := c.sendCommand(ctx, cmd, arg)
+	return err
+}
+
+// queryAdjustment is queryInt for the adjustments, which may be negative.
+func (c *Client) queryAdjustment(ctx context.Context, cmd string) (int, error) {
+	res, err := c.sendCommand(ctx, cmd, "?")
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := parseSignedNumber(res)
+	if !ok {
+		return 0, &CommandError{Cmd: cmd, Arg: "?", Raw: res, Err: ErrInvalidResponse}
+	}
+
+	return n, nil
+}
+
+// SetHorizontalPosition adjusts the horizontal position of the picture.
+func (c *Client) SetHorizontalPosition(ctx context.Context, pos int) error {
+	return c.setAdjustment(ctx, "HPOS", pos)
+}
+
+// HorizontalPosition returns the horizontal position of the picture.
+func (c *Client) HorizontalPosition(ctx context.Context) (int, error) {
+	return c.queryAdjustment(ctx, "HPOS")
+}
+
+// SetVerticalPosition adjusts the vertical position of the picture.
+func (c *Client) SetVerticalPosition(ctx context.Context, pos int) error {
+	return c.setAdjustment(ctx, "VPOS", pos)
+}
+
+// VerticalPosition returns the vertical position of the picture.
+func (c *Client) VerticalPosition(ctx context.Context) (int, error) {
+	return c.queryAdjustment(ctx, "VPOS")
+}
+
+// SetClock adjusts the clock of the analog PC input.
+func (c *Client) SetClock(ctx context.Context, clock int) error {
+	return c.setAdjustment(ctx, "CLCK", clock)
+}
+
+// Clock returns the clock of the analog PC input.
+func (c *Client) Clock(ctx context.Context) (int, error) {
+	return c.queryAdjustment(ctx, "CLCK")
+}
+
+// SetPhase adjusts the phase of the analog PC input.
+func (c *Client) SetPhase(ctx context.Context, phase int) error {
+	return c.setAdjustment(ctx, "PHSE", phase)
+}
+
+// Phase returns the phase of the analog PC input.
+func (c *Client) Phase(ctx context.Context) (int, error) {
+	return c.queryAdjustment(ctx, "PHSE")
+}
+
+// A Mode3D is a 3D display mode of AQUOS models supporting 3D.
+type Mode3D int
+
+// 3D modes.
+const (
+	Mode3DOff              Mode3D = iota // 3D off
+	Mode3DFrom2D                         // 2D to 3D conversion
+	Mode3DSideBySide                     // side by side
+	Mode3DTopAndBottom                   // top and bottom
+	Mode2DFromSideBySide                 // 3D to 2D (side by side)
+	Mode2DFromTopAndBottom               // 3D to 2D (top and bottom)
+	Mode3DAuto                           // 3D auto
+	Mode2DAuto                           // 2D auto
+)
+
+// Set3DMode changes the 3D mode.
+func (c *Client) Set3DMode(ctx context.Context, mode Mode3D) error {
+	if mode < Mode3DOff || mode > Mode2DAuto {
+		return fmt.Errorf("%w: 3D mode (%d)", ErrInvalidArgument, mode)
+	}
+
+	arg := strconv.Itoa(int(mode))
+	_, err := c.sendCommand(ctx, "TDCH", arg)
+	return err
+}
+
+// Get3DMode returns the current 3D mode.
+func (c *Client) Get3DMode(ctx context.Context) (Mode3D, error) {
+	mode, err := c.queryInt(ctx, "TDCH")
+	if err != nil {
+		return 0, err
+	}
+
+	return Mode3D(mode), nil
+}
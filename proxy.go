@@ -0,0 +1,198 @@
+package aquos
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dialProxy connects to addr through the SOCKS5 or HTTP CONNECT proxy
+// proxy, reaching the proxy with dial.
+func dialProxy(ctx context.Context, proxy *url.URL, dial dialFunc, addr string) (net.Conn, error) {
+	var handshake func(net.Conn, *url.URL, string) error
+	switch proxy.Scheme {
+	case "socks5", "socks5h":
+		handshake = socks5Handshake
+	case "http":
+		handshake = httpConnect
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme (%s)", proxy.Scheme)
+	}
+
+	conn, err := dial(ctx, "tcp", proxy.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	err = handshake(conn, proxy, addr)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", proxy.Host, err)
+	}
+
+	return conn, nil
+}
+
+// socks5Handshake implements the CONNECT command of RFC 1928 with the
+// username/password authentication of RFC 1929.
+func socks5Handshake(conn net.Conn, proxy *url.URL, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port (%s)", portStr)
+	}
+
+	// greeting
+	method := byte(0x00) // no authentication
+	if proxy.User != nil {
+		method = 0x02 // username/password
+	}
+	_, err = conn.Write([]byte{0x05, 0x01, method})
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 2)
+	_, err = io.ReadFull(conn, buf)
+	if err != nil {
+		return err
+	}
+	if buf[0] != 0x05 || buf[1] != method {
+		return errors.New("authentication method rejected")
+	}
+
+	if method == 0x02 {
+		user := proxy.User.Username()
+		pass, _ := proxy.User.Password()
+		if len(user) > 255 || len(pass) > 255 {
+			return errors.New("username or password too long")
+		}
+		req := []byte{0x01, byte(len(user))}
+		req = append(req, user...)
+		req = append(req, byte(len(pass)))
+		req = append(req, pass...)
+		_, err = conn.Write(req)
+		if err != nil {
+			return err
+		}
+		_, err = io.ReadFull(conn, buf)
+		if err != nil {
+			return err
+		}
+		if buf[1] != 0x00 {
+			return errors.New("authentication failed")
+		}
+	}
+
+	// connect
+	req := []byte{0x05, 0x01, 0x00}
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return fmt.Errorf("host name too long (%s)", host)
+		}
+		req = append(req, 0x03, byte(len(host)))
+		req = append(req, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(req, 0x01)
+		req = append(req, ip4...)
+	} else {
+		req = append(req, 0x04)
+		req = append(req, ip.To16()...)
+	}
+	req = append(req, byte(port>>8), byte(port))
+	_, err = conn.Write(req)
+	if err != nil {
+		return err
+	}
+
+	head := make([]byte, 4)
+	_, err = io.ReadFull(conn, head)
+	if err != nil {
+		return err
+	}
+	if head[1] != 0x00 {
+		return fmt.Errorf("connect failed (reply %d)", head[1])
+	}
+
+	// skip the bound address
+	var n int
+	switch head[3] {
+	case 0x01:
+		n = net.IPv4len
+	case 0x04:
+		n = net.IPv6len
+	case 0x03:
+		_, err = io.ReadFull(conn, buf[:1])
+		if err != nil {
+			return err
+		}
+		n = int(buf[0])
+	default:
+		return fmt.Errorf("invalid address type (%d)", head[3])
+	}
+	_, err = io.ReadFull(conn, make([]byte, n+2))
+	return err
+}
+
+// httpConnect opens a tunnel with the CONNECT method of HTTP/1.1.
+func httpConnect(conn net.Conn, proxy *url.URL, addr string) error {
+	req := &http.Request{
+		Method: "CONNECT",
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if proxy.User != nil {
+		pass, _ := proxy.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + pass))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	err := req.Write(conn)
+	if err != nil {
+		return err
+	}
+
+	// read the header byte by byte so that no data from AQUOS (e.g. the
+	// login prompt) is consumed with it
+	var head []byte
+	b := make([]byte, 1)
+	for !bytes.HasSuffix(head, []byte("\r\n\r\n")) {
+		if len(head) > 4096 {
+			return errors.New("response header too long")
+		}
+		_, err = conn.Read(b)
+		if err != nil {
+			return err
+		}
+		head = append(head, b[0])
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(head)), req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("connect failed (%s)", res.Status)
+	}
+
+	return nil
+}
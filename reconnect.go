@@ -0,0 +1,83 @@
+package aquos
+
+import (
+	"context"
+	"time"
+)
+
+// A ReconnectPolicy controls how a Client connects again when AQUOS drops
+// the connection. Attempts are spaced with an exponential backoff.
+//
+// Note that a command is sent again after reconnecting, so a toggle or a
+// key press may take effect twice if the connection was dropped after
+// AQUOS received it.
+type ReconnectPolicy struct {
+	// MaxAttempts is the maximum number of connection attempts.
+	// Zero means 3.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the second attempt; the delay
+	// doubles with each further attempt. Zero means 100ms.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Zero means 5s.
+	MaxBackoff time.Duration
+}
+
+func (p *ReconnectPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 3
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the attempt following the n-th
+// failed one.
+func (p *ReconnectPolicy) backoff(n int) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 5 * time.Second
+	}
+	return backoff(n, min, max)
+}
+
+// backoff returns min doubled n-1 times, capped at max.
+func backoff(n int, min, max time.Duration) time.Duration {
+	d := min
+	for i := 1; i < n; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// reconnect must be called with c.mu held.
+func (c *Client) reconnect(ctx context.Context) (err error) {
+	c.close()
+
+	p := c.Reconnect
+	for n := 0; n < p.maxAttempts(); n++ {
+		if n > 0 {
+			err = c.sleep(ctx, p.backoff(n))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = c.connect(ctx)
+		if err == nil {
+			return nil
+		}
+		c.logf("aquos: reconnect attempt %d failed: %v", n+1, err)
+	}
+
+	return err
+}
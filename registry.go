@@ -0,0 +1,197 @@
+package aquos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// A Device describes how to reach a TV known by a Registry.
+type Device struct {
+	Address      string        `json:"address"`
+	MAC          string        `json:"mac,omitempty"`
+	Username     string        `json:"username,omitempty"`
+	Password     string        `json:"password,omitempty"`
+	ModelName    string        `json:"model,omitempty"`
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+}
+
+// Client returns a Client for the TV.
+func (d Device) Client() *Client {
+	return &Client{
+		Address:  d.Address,
+		Username: d.Username,
+		Password: d.Password,
+		MAC:      d.MAC,
+	}
+}
+
+// A Store persists the devices of a Registry.
+type Store interface {
+	// Load returns the devices saved, or none if nothing was saved yet.
+	Load() (map[string]Device, error)
+
+	// Save replaces the devices saved.
+	Save(devices map[string]Device) error
+}
+
+// A FileStore saves devices as JSON in the file at Path. As the file
+// holds credentials, it is only readable by its owner.
+type FileStore struct {
+	Path string
+}
+
+// DefaultRegistryPath returns the path of the registry file in the user
+// configuration directory.
+func DefaultRegistryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aquos", "devices.json"), nil
+}
+
+// Load implements Store.
+func (s FileStore) Load() (map[string]Device, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return map[string]Device{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var devices map[string]Device
+	err = json.Unmarshal(data, &devices)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Path, err)
+	}
+	return devices, nil
+}
+
+// Save implements Store. The file is replaced atomically.
+func (s FileStore) Save(devices map[string]Device) error {
+	data, err := json.MarshalIndent(devices, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.Path)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, ".devices-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(append(data, '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.Path)
+}
+
+// A Registry maps friendly names to the TVs they refer to, so that
+// applications can refer to TVs by name. It is safe for concurrent use.
+type Registry struct {
+	store Store
+
+	mu      sync.Mutex
+	devices map[string]Device
+}
+
+// NewRegistry returns an empty Registry persisted to store.
+func NewRegistry(store Store) *Registry {
+	return &Registry{
+		store:   store,
+		devices: make(map[string]Device),
+	}
+}
+
+// Load replaces the devices of the registry with the ones saved.
+func (r *Registry) Load() error {
+	devices, err := r.store.Load()
+	if err != nil {
+		return err
+	}
+	if devices == nil {
+		devices = make(map[string]Device)
+	}
+
+	r.mu.Lock()
+	r.devices = devices
+	r.mu.Unlock()
+	return nil
+}
+
+// Save saves the devices of the registry.
+func (r *Registry) Save() error {
+	r.mu.Lock()
+	devices := make(map[string]Device, len(r.devices))
+	for name, d := range r.devices {
+		devices[name] = d
+	}
+	r.mu.Unlock()
+
+	return r.store.Save(devices)
+}
+
+// Get returns the device named name.
+func (r *Registry) Get(name string) (Device, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.devices[name]
+	return d, ok
+}
+
+// Set adds or replaces the device named name.
+func (r *Registry) Set(name string, d Device) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.devices[name] = d
+}
+
+// Delete removes the device named name.
+func (r *Registry) Delete(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.devices, name)
+}
+
+// Names returns the sorted names of the devices.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.devices))
+	for name := range r.devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Client returns a Client for the device named name.
+func (r *Registry) Client(name string) (*Client, error) {
+	d, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("device %q does not exist", name)
+	}
+	return d.Client(), nil
+}
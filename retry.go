@@ -0,0 +1,80 @@
+package aquos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// A RetryPolicy controls how a Client sends a command again after it
+// failed, e.g. because AQUOS answers ERR for a moment after a power
+// transition. Attempts are spaced with an exponential backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. Zero means 2.
+	MaxAttempts int
+
+	// Backoff is the delay before the second attempt; the delay doubles
+	// with each further attempt. Zero means 200ms.
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Zero means 2s.
+	MaxBackoff time.Duration
+
+	// Jitter randomizes each delay by up to this fraction of it (0 - 1),
+	// so that clients do not retry in lockstep.
+	Jitter float64
+
+	// Retryable reports whether a failed command should be sent again.
+	// If nil, only commands rejected with ERR are.
+	Retryable func(error) bool
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 2
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return errors.Is(err, ErrCommandRejected)
+	}
+	return p.Retryable(err)
+}
+
+// backoff returns the delay before the attempt following the n-th
+// failed one.
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	min, max := p.Backoff, p.MaxBackoff
+	if min <= 0 {
+		min = 200 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 2 * time.Second
+	}
+
+	d := backoff(n, min, max)
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// sendCommand sends a command, retrying it as configured by c.Retry.
+func (c *Client) sendCommand(ctx context.Context, cmd, arg string) (string, error) {
+	p := c.Retry
+	for n := 1; ; n++ {
+		res, err := c.sendCommandOnce(ctx, cmd, arg)
+		if err == nil || p == nil || n >= p.maxAttempts() || ctx.Err() != nil || errors.Is(err, ErrClosed) || !p.retryable(err) {
+			return res, err
+		}
+
+		err = c.sleep(ctx, p.backoff(n))
+		if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package aquos
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultScanTimeout bounds the probe of a single address when
+// Discoverer.ScanTimeout is not set.
+var DefaultScanTimeout = 500 * time.Millisecond
+
+// DefaultScanConcurrency is the number of addresses probed at a time when
+// Discoverer.ScanConcurrency is not set.
+var DefaultScanConcurrency = 64
+
+// maxScanHosts caps the number of addresses of a subnet to scan.
+const maxScanHosts = 4096
+
+// scan probes the control port of every address of the subnets and
+// calls found for each one answering like AQUOS.
+func (d *Discoverer) scan(ctx context.Context, found func(DiscoveredTV)) error {
+	subnets := d.Subnets
+	if subnets == nil {
+		var err error
+		subnets, err = localSubnets()
+		if err != nil {
+			return err
+		}
+	}
+
+	var ips []net.IP
+	for _, s := range subnets {
+		hosts, err := subnetHosts(s)
+		if err != nil {
+			return err
+		}
+		ips = append(ips, hosts...)
+	}
+
+	timeout := d.ScanTimeout
+	if timeout <= 0 {
+		timeout = DefaultScanTimeout
+	}
+	n := d.ScanConcurrency
+	if n <= 0 {
+		n = DefaultScanConcurrency
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, n)
+loop:
+	for _, ip := range ips {
+		select {
+		case <-ctx.Done():
+			break loop
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(addr string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			tv, ok := probe(ctx, addr, timeout)
+			if ok {
+				found(tv)
+			}
+		}(net.JoinHostPort(ip.String(), DefaultPort))
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// probe reports whether AQUOS answers at addr, identifying it with
+// a model name query. A TV requiring a login or rejecting the query is
+// reported without it; any other answer must be a known model name.
+func probe(ctx context.Context, addr string, timeout time.Duration) (DiscoveredTV, bool) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c := &Client{
+		Address:         addr,
+		DialTimeout:     timeout,
+		CommandInterval: -1,
+	}
+	defer c.Close()
+
+	tv := DiscoveredTV{Addr: addr, Via: "scan"}
+	model, err := c.sendCommand(ctx, "MNRD", "1")
+	switch {
+	case err == nil:
+		if !isAQUOS("", model) {
+			// another service answering with a line, e.g. HTTP
+			return tv, false
+		}
+		tv.ModelName = model
+	case errors.Is(err, ErrLoginFailed), errors.Is(err, ErrCommandRejected):
+		// an AQUOS protocol answer
+		return tv, true
+	default:
+		return tv, false
+	}
+
+	name, err := c.sendCommand(ctx, "TVNM", "1")
+	if err == nil {
+		tv.Name = name
+	}
+	return tv, true
+}
+
+// subnetHosts returns the host addresses of the IPv4 subnet cidr.
+func subnetHosts(cidr string) ([]net.IP, error) {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	ip := n.IP.To4()
+	if ip == nil {
+		return nil, fmt.Errorf("%w: subnet (%s), must be IPv4", ErrInvalidArgument, cidr)
+	}
+	ones, bits := n.Mask.Size()
+	size := 1 << uint(bits-ones)
+	if size > maxScanHosts {
+		return nil, fmt.Errorf("%w: subnet (%s), too large to scan", ErrInvalidArgument, cidr)
+	}
+
+	first, last := 0, size
+	if size > 2 {
+		// skip the network and broadcast addresses
+		first, last = 1, size-1
+	}
+	base := binary.BigEndian.Uint32(ip)
+	hosts := make([]net.IP, 0, last-first)
+	for i := first; i < last; i++ {
+		h := make(net.IP, 4)
+		binary.BigEndian.PutUint32(h, base+uint32(i))
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
+
+// localSubnets returns the IPv4 subnets of the interfaces that are up,
+// narrowed to /24 around the address of the interface when larger.
+func localSubnets() ([]string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var subnets []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			n, ok := a.(*net.IPNet)
+			if !ok || n.IP.To4() == nil {
+				continue
+			}
+			mask := n.Mask
+			if ones, _ := mask.Size(); ones < 24 {
+				mask = net.CIDRMask(24, 32)
+			}
+			subnet := net.IPNet{IP: n.IP.Mask(mask), Mask: mask}
+			subnets = append(subnets, subnet.String())
+		}
+	}
+	if len(subnets) == 0 {
+		return nil, errors.New("no local IPv4 subnet to scan")
+	}
+	return subnets, nil
+}
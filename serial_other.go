@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package aquos
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+func openSerial(name string, baud int) (io.ReadWriteCloser, error) {
+	return nil, fmt.Errorf("serial port is not supported on %s", runtime.GOOS)
+}
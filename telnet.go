@@ -0,0 +1,82 @@
+package aquos
+
+import "io"
+
+// Telnet commands (RFC 854).
+const (
+	telnetSE   = 240
+	telnetSB   = 250
+	telnetWILL = 251
+	telnetDONT = 254
+	telnetIAC  = 255
+)
+
+// A telnetReader strips the telnet command sequences some AQUOS models
+// send during login.
+type telnetReader struct {
+	r     io.Reader
+	state int
+}
+
+// states of telnetReader
+const (
+	telnetData   = iota
+	telnetCmd    // after IAC
+	telnetOption // after WILL, WONT, DO or DONT
+	telnetSub    // in subnegotiation
+	telnetSubIAC // after IAC in subnegotiation
+)
+
+func (t *telnetReader) Read(p []byte) (int, error) {
+	for {
+		n, err := t.r.Read(p)
+		n = t.strip(p[:n])
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// strip removes the command sequences from b in place and returns the
+// length of the remaining data.
+func (t *telnetReader) strip(b []byte) int {
+	n := 0
+	for _, c := range b {
+		switch t.state {
+		case telnetData:
+			if c == telnetIAC {
+				t.state = telnetCmd
+				continue
+			}
+			b[n] = c
+			n++
+		case telnetCmd:
+			switch {
+			case c == telnetIAC:
+				// escaped 0xff
+				b[n] = c
+				n++
+				t.state = telnetData
+			case c == telnetSB:
+				t.state = telnetSub
+			case c >= telnetWILL && c <= telnetDONT:
+				t.state = telnetOption
+			default:
+				t.state = telnetData
+			}
+		case telnetOption:
+			t.state = telnetData
+		case telnetSub:
+			if c == telnetIAC {
+				t.state = telnetSubIAC
+			}
+		case telnetSubIAC:
+			if c == telnetSE {
+				t.state = telnetData
+			} else {
+				t.state = telnetSub
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,120 @@
+package aquos
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A TranscriptEntry is a chunk of bytes exchanged with AQUOS.
+type TranscriptEntry struct {
+	Time time.Time
+	Sent bool // sent to AQUOS, rather than received
+	Data []byte
+}
+
+// A Recorder writes a transcript of the bytes exchanged with AQUOS, one
+// entry per line: the time, ">" for bytes sent or "<" for bytes received,
+// and the bytes as a quoted Go string. ReadTranscript reads it back.
+// Note that the transcript includes the credentials sent on login.
+type Recorder struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewRecorder returns a Recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Err returns the first error writing the transcript.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// Wrap returns rwc recording the bytes read and written.
+func (r *Recorder) Wrap(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	return &recordedConn{ReadWriteCloser: rwc, r: r}
+}
+
+func (r *Recorder) record(sent bool, data []byte) {
+	dir := "<"
+	if sent {
+		dir = ">"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	_, r.err = fmt.Fprintf(r.w, "%s %s %s\n", time.Now().Format(time.RFC3339Nano), dir, strconv.Quote(string(data)))
+}
+
+type recordedConn struct {
+	io.ReadWriteCloser
+	r *Recorder
+}
+
+func (c *recordedConn) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	if n > 0 {
+		c.r.record(false, p[:n])
+	}
+	return n, err
+}
+
+func (c *recordedConn) Write(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Write(p)
+	if n > 0 {
+		c.r.record(true, p[:n])
+	}
+	return n, err
+}
+
+// ReadTranscript reads a transcript written by a Recorder. Blank lines
+// and lines starting with "#" are ignored, so transcripts can be edited
+// and commented by hand.
+func ReadTranscript(r io.Reader) ([]TranscriptEntry, error) {
+	var entries []TranscriptEntry
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 || (fields[1] != ">" && fields[1] != "<") {
+			return nil, fmt.Errorf("transcript line %d: invalid entry", n)
+		}
+		t, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("transcript line %d: %w", n, err)
+		}
+		data, err := strconv.Unquote(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("transcript line %d: %w", n, err)
+		}
+
+		entries = append(entries, TranscriptEntry{
+			Time: t,
+			Sent: fields[1] == ">",
+			Data: []byte(data),
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
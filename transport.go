@@ -0,0 +1,35 @@
+package aquos
+
+import (
+	"context"
+	"io"
+)
+
+// NewSerialClient returns a Client speaking to AQUOS over the RS-232C port
+// portName (e.g. "/dev/ttyUSB0") at baud bits per second, 8 data bits, no
+// parity and 1 stop bit. AQUOS uses 9600 baud by default.
+// The port is opened by Connect or by the first command.
+func NewSerialClient(portName string, baud int) *Client {
+	return &Client{
+		Open: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			return openSerial(portName, baud)
+		},
+	}
+}
+
+// WrapConn returns a Client speaking over the established connection conn,
+// e.g. a net.Conn forwarded through SSH or one end of a net.Pipe. Set the
+// credentials before the first command if AQUOS requires a login.
+// The client cannot connect again once conn is closed.
+func WrapConn(conn io.ReadWriteCloser) *Client {
+	return &Client{
+		Open: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			if conn == nil {
+				return nil, ErrClosed
+			}
+			rwc := conn
+			conn = nil
+			return rwc, nil
+		},
+	}
+}
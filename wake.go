@@ -0,0 +1,161 @@
+package aquos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// WakeOnLANAddr is the address Wake-on-LAN packets are sent to.
+var WakeOnLANAddr = "255.255.255.255:9"
+
+// WakeOnLAN sends a Wake-on-LAN magic packet for the MAC address mac to
+// WakeOnLANAddr, to turn on a TV that is powered off or in a standby
+// ignoring the network. Wake-on-LAN must be enabled on the TV.
+func WakeOnLAN(mac string) error {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return fmt.Errorf("%w: MAC address (%s)", ErrInvalidArgument, mac)
+	}
+	if len(hw) != 6 {
+		return fmt.Errorf("%w: MAC address (%s), must be 6 bytes", ErrInvalidArgument, mac)
+	}
+
+	packet := make([]byte, 0, 102)
+	for i := 0; i < 6; i++ {
+		packet = append(packet, 0xff)
+	}
+	for i := 0; i < 16; i++ {
+		packet = append(packet, hw...)
+	}
+
+	conn, err := net.Dial("udp", WakeOnLANAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write(packet)
+	return err
+}
+
+// LookupMAC returns the MAC address of the host at ip on the local
+// network from the ARP table. The table has an entry only after traffic
+// with the host, such as a connection. It returns ErrNoMAC if there is no
+// entry.
+func LookupMAC(ip net.IP) (string, error) {
+	return lookupARP(ip)
+}
+
+// Wake sends a Wake-on-LAN packet to the TV. It returns ErrNoMAC if the
+// MAC address of the TV is not known.
+func (c *Client) Wake() error {
+	mac := c.mac()
+	if mac == "" {
+		return ErrNoMAC
+	}
+	return WakeOnLAN(mac)
+}
+
+func (c *Client) mac() string {
+	c.infoMu.RLock()
+	defer c.infoMu.RUnlock()
+
+	return c.MAC
+}
+
+// remoteIP returns the address of the TV if the client is connected to it
+// directly over IP. It must be called with c.mu held.
+func (c *Client) remoteIP() net.IP {
+	rwc := c.conn
+	if r, ok := rwc.(*recordedConn); ok {
+		rwc = r.ReadWriteCloser
+	}
+	conn, ok := rwc.(net.Conn)
+	if !ok || c.Proxy != nil {
+		return nil
+	}
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return nil
+	}
+	return addr.IP
+}
+
+// resolveMAC sets MAC from the ARP table unless it is already set.
+func (c *Client) resolveMAC(ip net.IP) {
+	if ip == nil || c.mac() != "" {
+		return
+	}
+
+	mac, err := LookupMAC(ip)
+	if err != nil {
+		c.logf("aquos: MAC lookup of %s failed: %v", ip, err)
+		return
+	}
+
+	c.infoMu.Lock()
+	c.MAC = mac
+	c.infoMu.Unlock()
+}
+
+// DefaultWakeTimeout bounds the attempts of Client.AutoWake when the
+// context of Power has no deadline.
+var DefaultWakeTimeout = 30 * time.Second
+
+// wake keeps trying to turn on a TV that failed to answer the power on
+// command, until it succeeds or ctx is done. Each attempt sends
+// Wake-on-LAN if the MAC address is known and enables IP control in
+// standby, as a TV in deep standby ignores the power on command
+// otherwise. It returns the error of the last attempt, or ErrClosed once
+// Close is called, closes being the count of calls to Close before the
+// power on command.
+func (c *Client) wake(ctx context.Context, closes int, err error) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultWakeTimeout)
+		defer cancel()
+	}
+
+	for n := 1; ; n++ {
+		c.logf("aquos: waking up (attempt %d): %v", n, err)
+		if c.mac() != "" {
+			if werr := c.Wake(); werr != nil {
+				c.logf("aquos: Wake-on-LAN failed: %v", werr)
+			}
+		}
+		if c.sleep(ctx, backoff(n, 500*time.Millisecond, 5*time.Second)) != nil {
+			return err
+		}
+		if c.closeCount() != closes {
+			return ErrClosed
+		}
+
+		err = c.EnableIPControlInStandby(ctx)
+		if err == nil || errors.Is(err, ErrCommandRejected) {
+			// models without RSPW may still turn on
+			_, err = c.sendCommand(ctx, "POWR", "1")
+		}
+		if err == nil || !canWake(err) {
+			return err
+		}
+	}
+}
+
+// canWake reports whether a failed power on command may succeed after
+// waking the TV.
+func canWake(err error) bool {
+	return !errors.Is(err, ErrLoginFailed) &&
+		!errors.Is(err, ErrNotConnected) &&
+		!errors.Is(err, ErrClosed) &&
+		!errors.Is(err, context.Canceled)
+}
+
+func (c *Client) closeCount() int {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	return c.closes
+}
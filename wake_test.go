@@ -0,0 +1,100 @@
+package aquos_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/noocsharp/go-aquos"
+	"github.com/noocsharp/go-aquos/aquostest"
+)
+
+func TestAutoWakeWithoutRSPW(t *testing.T) {
+	s := aquostest.NewUnstartedServer()
+	powerOn := 0
+	s.Handler = func(cmd, arg string) (string, bool) {
+		switch {
+		case cmd == "RSPW":
+			return "ERR", true
+		case cmd == "POWR" && arg == "1":
+			// the TV wakes up after the first attempt
+			powerOn++
+			if powerOn == 1 {
+				return "ERR", true
+			}
+			return "OK", true
+		}
+		return "", false
+	}
+	s.Start()
+	defer s.Close()
+
+	c := s.Client()
+	c.AutoWake = true
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := c.Power(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutoWakeClose(t *testing.T) {
+	s := aquostest.NewUnstartedServer()
+	rejected := make(chan struct{}, 100)
+	s.Handler = func(cmd, arg string) (string, bool) {
+		if cmd == "POWR" && arg == "1" {
+			rejected <- struct{}{}
+			return "ERR", true
+		}
+		return "", false
+	}
+	s.Start()
+	defer s.Close()
+
+	c := s.Client()
+	c.AutoWake = true
+	var mu sync.Mutex
+	conns := 0
+	open := c.Open
+	c.Open = func(ctx context.Context) (io.ReadWriteCloser, error) {
+		mu.Lock()
+		conns++
+		mu.Unlock()
+		return open(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.Power(ctx, true)
+	}()
+
+	<-rejected
+	c.Close()
+	mu.Lock()
+	closed := conns
+	mu.Unlock()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, aquos.ErrClosed) {
+			t.Errorf("Power error = %v, want ErrClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Power kept waking up after Close")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if conns != closed {
+		t.Errorf("%d connections after Close", conns-closed)
+	}
+}